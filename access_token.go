@@ -0,0 +1,171 @@
+package sjwt
+
+import (
+	"encoding/json"
+	"maps"
+	"time"
+)
+
+// ClientID is the OAuth 2.0 client the token was issued to
+const ClientID = "client_id"
+
+// AccessTokenParams are the values of a single RFC 9068 access token
+type AccessTokenParams struct {
+	// Subject is the resource owner, or the client itself for client credentials grants
+	Subject string
+
+	// ClientID is the client the token is issued to
+	ClientID string
+
+	// Audience are the resource servers the token is intended for
+	Audience []string
+
+	// Scopes, Groups, Roles and Entitlements are optional authorization claims
+	Scopes       []string
+	Groups       []string
+	Roles        []string
+	Entitlements []string
+
+	// Claims are additional claims, such as auth_time or acr. Registered claims set by the issuer are overwritten
+	Claims Claims
+}
+
+// AccessTokenIssuer issues RFC 9068 JWT access tokens with the at+jwt typ header
+type AccessTokenIssuer struct {
+	// Issuer is the authorization server issuer identifier
+	Issuer string
+
+	// Secret signs the access tokens
+	Secret []byte
+
+	// TTL is how long access tokens are valid, 15 minutes when zero
+	TTL time.Duration
+}
+
+// NewAccessTokenIssuer will initiate a new access token issuer with the default lifetime
+func NewAccessTokenIssuer(issuer string, secret []byte) *AccessTokenIssuer {
+	return &AccessTokenIssuer{
+		Issuer: issuer,
+		Secret: secret,
+		TTL:    defaultAccessTTL,
+	}
+}
+
+// Issue generates an access token with iss, exp, aud, sub, client_id, iat and jti set.
+// Subject, ClientID and Audience are required
+func (i *AccessTokenIssuer) Issue(params AccessTokenParams) (string, error) {
+	now := time.Now()
+	required := []struct{ name, value string }{
+		{Issuer, i.Issuer},
+		{Subject, params.Subject},
+		{ClientID, params.ClientID},
+	}
+	var errs []error
+	for _, r := range required {
+		if r.value == "" {
+			errs = append(errs, &ValidationError{Claim: r.name, Err: ErrNotFound, Reason: "is required", Time: now})
+		}
+	}
+	if len(params.Audience) == 0 {
+		errs = append(errs, &ValidationError{Claim: Audience, Err: ErrNotFound, Reason: "is required", Time: now})
+	}
+	if err := joinErrors(errs); err != nil {
+		return "", err
+	}
+
+	claims := maps.Clone(params.Claims)
+	if claims == nil {
+		claims = Claims{}
+	}
+	claims.SetIssuer(i.Issuer)
+	claims.SetSubject(params.Subject)
+	claims.Set(ClientID, params.ClientID)
+	claims.SetAudience(params.Audience)
+	claims.SetIssuedAt(now)
+	ttl := i.TTL
+	if ttl == 0 {
+		ttl = defaultAccessTTL
+	}
+	claims.SetExpiresAt(now.Add(ttl))
+	claims.SetTokenID()
+	if len(params.Scopes) > 0 {
+		claims.SetScopes(params.Scopes)
+	}
+	if len(params.Groups) > 0 {
+		claims.SetGroups(params.Groups)
+	}
+	if len(params.Roles) > 0 {
+		claims.SetRoles(params.Roles)
+	}
+	if len(params.Entitlements) > 0 {
+		claims.SetEntitlements(params.Entitlements)
+	}
+
+	claimsEnc, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return signType(accessTokenType, claimsEnc, i.Secret)
+}
+
+// AccessTokenValidator validates RFC 9068 JWT access tokens on resource servers
+type AccessTokenValidator struct {
+	// Issuer is the authorization server issuer identifier, iss must equal it exactly
+	Issuer string
+
+	// Audience is the resource server identifier, aud must contain it
+	Audience string
+
+	// Secret verifies the signature
+	Secret []byte
+
+	// Options are additional validate options, such as WithLeeway or WithRevocationStore.
+	// They can only add checks, Issuer and Audience are always required
+	Options []ValidateOption
+}
+
+// NewAccessTokenValidator will initiate a new access token validator for the issuer and resource server
+func NewAccessTokenValidator(issuer string, audience string, secret []byte) *AccessTokenValidator {
+	return &AccessTokenValidator{
+		Issuer:   issuer,
+		Audience: audience,
+		Secret:   secret,
+	}
+}
+
+// Validate verifies the at+jwt typ header and signature, checks iss, aud and exp,
+// requires every RFC 9068 claim with the validator options followed by the per call options and returns the claims
+func (v *AccessTokenValidator) Validate(token string, opts ...ValidateOption) (Claims, error) {
+	// Without an issuer or audience any authorization server or resource server would be accepted
+	if v.Issuer == "" {
+		return nil, ErrTokenIssuerInvalid
+	}
+	if v.Audience == "" {
+		return nil, ErrTokenAudienceInvalid
+	}
+
+	claimsByte, err := verifyPayloadType(token, v.Secret, accessTokenType)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := decodeClaims(claimsByte)
+	if err != nil {
+		return nil, err
+	}
+
+	validateOpts := append([]ValidateOption{
+		WithRequired(Issuer, ExpiresAt, Audience, Subject, ClientID, IssuedAt, TokenID),
+	}, v.Options...)
+	validateOpts = append(validateOpts, opts...)
+
+	// Issuer and Audience are checked exactly so options can only add checks
+	err = claims.validateWith(validateOpts, func(_ *validateOptions, now time.Time) []error {
+		return claims.validateIssuerAudience(v.Issuer, v.Audience, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
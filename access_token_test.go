@@ -0,0 +1,159 @@
+package sjwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	asIssuer    = "https://as.example.com"
+	rsAudience  = "https://api.example.com"
+	asClientID  = "s6BhdRkqt3"
+	asSubjectID = "user-1"
+)
+
+func issueAccessToken(t *testing.T, params AccessTokenParams) string {
+	t.Helper()
+
+	token, err := NewAccessTokenIssuer(asIssuer, secretKey).Issue(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestAccessTokenIssuer(t *testing.T) {
+	token := issueAccessToken(t, AccessTokenParams{
+		Subject:      asSubjectID,
+		ClientID:     asClientID,
+		Audience:     []string{rsAudience},
+		Scopes:       []string{"orders:read"},
+		Roles:        []string{"admin"},
+		Entitlements: []string{"invoice-42"},
+		Claims:       Claims{"acr": "mfa", Issuer: "ignored"},
+	})
+
+	headerBytes, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil || header.Typ != "at+jwt" {
+		t.Errorf("expected at+jwt typ header, got %s", headerBytes)
+	}
+
+	// Access tokens are not plain JWTs
+	if _, err := Parse(token); !errors.Is(err, ErrTokenHeaderInvalid) {
+		t.Errorf("expected Parse to reject at+jwt, got %v", err)
+	}
+
+	claims, err := NewAccessTokenValidator(asIssuer, rsAudience, secretKey).Validate(token)
+	if err != nil {
+		t.Fatalf("Validate was not successful when it should be: %v", err)
+	}
+	for _, name := range []string{Issuer, ExpiresAt, Audience, Subject, ClientID, IssuedAt, TokenID} {
+		if !claims.Has(name) {
+			t.Errorf("expected %s to be set", name)
+		}
+	}
+	if issuer, _ := claims.GetIssuer(); issuer != asIssuer {
+		t.Errorf("issuer should not be overwritten by params claims, got %s", issuer)
+	}
+	if acr, _ := claims.GetStr("acr"); acr != "mfa" || !claims.HasScope("orders:read") || !claims.HasRole("admin") {
+		t.Errorf("unexpected claims %v", claims)
+	}
+	if entitlements, _ := claims.GetEntitlements(); len(entitlements) != 1 || entitlements[0] != "invoice-42" {
+		t.Errorf("unexpected entitlements %v", entitlements)
+	}
+
+	_, err = NewAccessTokenIssuer(asIssuer, secretKey).Issue(AccessTokenParams{Subject: asSubjectID})
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !errors.Is(err, ErrNotFound) || !ok || len(joined.Unwrap()) != 2 {
+		t.Errorf("expected missing client_id and aud, got %v", err)
+	}
+}
+
+func TestAccessTokenIssuerDefaultTTL(t *testing.T) {
+	issuer := &AccessTokenIssuer{Issuer: asIssuer, Secret: secretKey}
+	token, err := issuer.Issue(AccessTokenParams{Subject: asSubjectID, ClientID: asClientID, Audience: []string{rsAudience}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAccessTokenValidator(asIssuer, rsAudience, secretKey).Validate(token); err != nil {
+		t.Errorf("expected the default lifetime, got %v", err)
+	}
+}
+
+func TestAccessTokenValidator(t *testing.T) {
+	params := AccessTokenParams{Subject: asSubjectID, ClientID: asClientID, Audience: []string{rsAudience}}
+	token := issueAccessToken(t, params)
+	validator := NewAccessTokenValidator(asIssuer, rsAudience, secretKey)
+
+	if _, err := NewAccessTokenValidator("https://other.example.com", rsAudience, secretKey).Validate(token); !errors.Is(err, ErrTokenIssuerInvalid) {
+		t.Errorf("expected ErrTokenIssuerInvalid, got %v", err)
+	}
+	if _, err := NewAccessTokenValidator(asIssuer, "https://other.example.com", secretKey).Validate(token); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+	if _, err := validator.Validate(token, WithRequired("acr")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected per call options to apply, got %v", err)
+	}
+
+	// Options cannot widen the issuer or audience
+	other := issueAccessToken(t, AccessTokenParams{Subject: asSubjectID, ClientID: asClientID, Audience: []string{"https://other.example.com"}})
+	if _, err := validator.Validate(other, WithAudience("https://other.example.com")); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+	if _, err := NewAccessTokenValidator(asIssuer, "", secretKey).Validate(token); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid without an audience, got %v", err)
+	}
+
+	// Plain JWTs with the same claims are not access tokens
+	claims := New()
+	claims.SetIssuer(asIssuer)
+	claims.SetSubject(asSubjectID)
+	claims.Set(ClientID, asClientID)
+	claims.SetAudience([]string{rsAudience})
+	claims.SetIssuedAt(time.Now())
+	claims.SetExpiresIn(time.Hour)
+	claims.SetTokenID()
+	jwt, _ := claims.Generate(secretKey)
+	if _, err := validator.Validate(jwt); !errors.Is(err, ErrTokenHeaderInvalid) {
+		t.Errorf("expected ErrTokenHeaderInvalid, got %v", err)
+	}
+
+	// The media type form of the typ header is accepted
+	claimsEnc, _ := json.Marshal(claims)
+	mediaType, _ := signType("application/at+jwt", claimsEnc, secretKey)
+	if _, err := validator.Validate(mediaType); err != nil {
+		t.Errorf("expected application/at+jwt to be accepted, got %v", err)
+	}
+
+	// Every RFC 9068 claim is required
+	claims.Del(ClientID)
+	claimsEnc, _ = json.Marshal(claims)
+	missing, _ := signType(accessTokenType, claimsEnc, secretKey)
+	var validationErr *ValidationError
+	if _, err := validator.Validate(missing); !errors.As(err, &validationErr) || validationErr.Claim != ClientID {
+		t.Errorf("expected missing client_id, got %v", err)
+	}
+}
+
+func TestAccessTokenValidatorReplayAfterChecks(t *testing.T) {
+	cache := NewReplayCache()
+	token := issueAccessToken(t, AccessTokenParams{Subject: asSubjectID, ClientID: asClientID, Audience: []string{"https://b.example.com"}})
+
+	// A token for another resource server does not use up its jti
+	if _, err := NewAccessTokenValidator(asIssuer, "https://a.example.com", secretKey).Validate(token, WithReplayCache(cache)); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Fatalf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+	validator := NewAccessTokenValidator(asIssuer, "https://b.example.com", secretKey)
+	if _, err := validator.Validate(token, WithReplayCache(cache)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+	if _, err := validator.Validate(token, WithReplayCache(cache)); !errors.Is(err, ErrTokenReplayed) {
+		t.Errorf("expected ErrTokenReplayed, got %v", err)
+	}
+}
@@ -2,6 +2,9 @@ package sjwt
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 )
 
@@ -43,25 +46,282 @@ func (c Claims) ToStruct(struc any) error {
 	return nil
 }
 
-// Validate checks expiration and not before times
-func (c Claims) Validate() error {
-	now := time.Now().Unix()
+// Validate checks expiration and not before times along with any additional options.
+// Each failure is returned as a *ValidationError and multiple failures are joined with errors.Join
+func (c Claims) Validate(opts ...ValidateOption) error {
+	o := newValidateOptions(opts)
+	now := o.now()
+	var errs []error
+
+	// Check required claims are present
+	for i, name := range o.required {
+		if !c.Has(name) && !slices.Contains(o.required[:i], name) {
+			errs = append(errs, &ValidationError{Claim: name, Err: ErrNotFound, Reason: "is required", Time: now})
+		}
+	}
 
 	// Check if not before at is set and if current time hasnt started yet
 	if c.Has(NotBeforeAt) {
-		nbf, _ := c.GetNotBeforeAt()
-		if now < nbf {
-			return ErrTokenNotYetValid
+		nbf, err := c.GetNotBeforeAtTime()
+		switch {
+		case err != nil:
+			errs = append(errs, invalidNumericDate(c, NotBeforeAt, now))
+		case now.Add(o.leeway).Before(nbf):
+			errs = append(errs, &ValidationError{
+				Claim:    NotBeforeAt,
+				Err:      ErrTokenNotYetValid,
+				Reason:   fmt.Sprintf("valid in %v", roundDuration(nbf.Sub(now))),
+				Expected: nbf,
+				Actual:   now,
+				Time:     now,
+			})
 		}
 	}
 
 	// Check if expiration at is set and if current time is passed
 	if c.Has(ExpiresAt) {
-		exp, _ := c.GetExpiresAt()
-		if now >= exp {
-			return ErrTokenHasExpired
+		exp, err := c.GetExpiresAtTime()
+		switch {
+		case err != nil:
+			errs = append(errs, invalidNumericDate(c, ExpiresAt, now))
+		case !now.Add(-o.leeway).Before(exp):
+			errs = append(errs, &ValidationError{
+				Claim:    ExpiresAt,
+				Err:      ErrTokenHasExpired,
+				Reason:   fmt.Sprintf("expired %v ago", roundDuration(now.Sub(exp))),
+				Expected: exp,
+				Actual:   now,
+				Time:     now,
+			})
+		}
+	}
+
+	// Check issued at and the token lifetime
+	errs = append(errs, c.validateIssuedAt(o, now)...)
+
+	// Check if audience contains one of the expected audiences
+	if len(o.audiences) > 0 && !slices.ContainsFunc(o.audiences, c.HasAudience) {
+		audience, _ := c.GetAudience()
+		errs = append(errs, &ValidationError{
+			Claim:    Audience,
+			Err:      ErrTokenAudienceInvalid,
+			Reason:   fmt.Sprintf("%v does not contain any of %v", audience, o.audiences),
+			Expected: o.audiences,
+			Actual:   audience,
+			Time:     now,
+		})
+	}
+
+	// Check if issuer is one of the expected issuers
+	if len(o.issuers) > 0 {
+		issuer, _ := c.GetIssuer()
+		if !slices.Contains(o.issuers, issuer) {
+			errs = append(errs, &ValidationError{
+				Claim:    Issuer,
+				Err:      ErrTokenIssuerInvalid,
+				Reason:   fmt.Sprintf("%q is not one of %v", issuer, o.issuers),
+				Expected: o.issuers,
+				Actual:   issuer,
+				Time:     now,
+			})
+		}
+	}
+
+	// Check the token is bound to the client certificate
+	if o.certificateBound {
+		errs = append(errs, c.validateCertificate(o, now)...)
+	}
+
+	// Check the token id has not been revoked
+	errs = append(errs, c.validateRevocation(o, now)...)
+
+	// Check custom claims against the schemas, reporting each violation like the other claims
+	for _, schema := range o.schemas {
+		err := schema.Validate(c)
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		for _, v := range schemaErr.Violations {
+			errs = append(errs, &ValidationError{Claim: v.Claim, Err: v.Err, Reason: v.Reason, Time: now})
+		}
+	}
+
+	// Record one-time tokens last so invalid tokens do not use up their token id
+	if o.replay != nil && len(errs) == 0 {
+		errs = append(errs, c.validateReplay(o, now)...)
+	}
+
+	return joinErrors(errs)
+}
+
+// invalidNumericDate describes a time claim that is not a json number
+func invalidNumericDate(c Claims, name string, now time.Time) error {
+	return &ValidationError{Claim: name, Err: ErrClaimValueInvalid, Reason: "must be a numeric date", Actual: c[name], Time: now}
+}
+
+// validateIssuedAt checks issued at is not in the future or too old and that the lifetime is not too long
+func (c Claims) validateIssuedAt(o *validateOptions, now time.Time) []error {
+	var errs []error
+	iat, iatErr := c.GetIssuedAtTime()
+
+	// A present but malformed issued at cannot satisfy any issued at check
+	if iatErr != nil && !errors.Is(iatErr, ErrNotFound) && (o.noFutureIssuedAt || o.maxAge > 0 || o.maxLifetime > 0) {
+		errs = append(errs, invalidNumericDate(c, IssuedAt, now))
+	}
+
+	if o.noFutureIssuedAt && iatErr == nil && iat.After(now.Add(o.leeway)) {
+		errs = append(errs, &ValidationError{
+			Claim:    IssuedAt,
+			Err:      ErrTokenIssuedInFuture,
+			Reason:   fmt.Sprintf("issued %v in the future", roundDuration(iat.Sub(now))),
+			Expected: now,
+			Actual:   iat,
+			Time:     now,
+		})
+	}
+
+	if o.maxAge > 0 {
+		switch {
+		case errors.Is(iatErr, ErrNotFound):
+			errs = append(errs, &ValidationError{Claim: IssuedAt, Err: ErrNotFound, Reason: "is required to check max age", Time: now})
+		case iatErr == nil && now.Sub(iat) > o.maxAge+o.leeway:
+			errs = append(errs, &ValidationError{
+				Claim:    IssuedAt,
+				Err:      ErrTokenTooOld,
+				Reason:   fmt.Sprintf("issued %v ago, max age is %v", roundDuration(now.Sub(iat)), o.maxAge),
+				Expected: o.maxAge,
+				Actual:   now.Sub(iat),
+				Time:     now,
+			})
+		}
+	}
+
+	if o.maxLifetime > 0 {
+		start := now
+		if iatErr == nil {
+			start = iat
+		}
+		exp, err := c.GetExpiresAtTime()
+		switch {
+		case err != nil:
+			errs = append(errs, &ValidationError{
+				Claim:    ExpiresAt,
+				Err:      ErrTokenLifetimeTooLong,
+				Reason:   fmt.Sprintf("is required, max lifetime is %v", o.maxLifetime),
+				Expected: o.maxLifetime,
+				Time:     now,
+			})
+		case exp.Sub(start) > o.maxLifetime:
+			errs = append(errs, &ValidationError{
+				Claim:    ExpiresAt,
+				Err:      ErrTokenLifetimeTooLong,
+				Reason:   fmt.Sprintf("lifetime is %v, max lifetime is %v", roundDuration(exp.Sub(start)), o.maxLifetime),
+				Expected: o.maxLifetime,
+				Actual:   exp.Sub(start),
+				Time:     now,
+			})
 		}
 	}
 
+	return errs
+}
+
+// validateWith runs Validate and the checks of a validator, which options cannot turn off or widen,
+// and records the token id in the replay cache only when both pass
+func (c Claims) validateWith(opts []ValidateOption, checks func(o *validateOptions, now time.Time) []error) error {
+	o := newValidateOptions(opts)
+	now := o.now()
+
+	var errs []error
+	if err := c.Validate(append(slices.Clone(opts), WithReplayCache(nil))...); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, checks(o, now)...)
+	if o.replay != nil && len(errs) == 0 {
+		errs = append(errs, c.validateReplay(o, now)...)
+	}
+
+	return joinErrors(errs)
+}
+
+// validateIssuerAudience checks iss equals issuer and aud contains audience when they are present,
+// for validators whose issuer and audience options must not widen what they accept
+func (c Claims) validateIssuerAudience(issuer string, audience string, now time.Time) []error {
+	var errs []error
+	if actual, err := c.GetIssuer(); err == nil && actual != issuer {
+		errs = append(errs, &ValidationError{
+			Claim:    Issuer,
+			Err:      ErrTokenIssuerInvalid,
+			Reason:   fmt.Sprintf("%q is not %q", actual, issuer),
+			Expected: issuer,
+			Actual:   actual,
+			Time:     now,
+		})
+	}
+	if c.Has(Audience) && !c.HasAudience(audience) {
+		actual, _ := c.GetAudience()
+		errs = append(errs, &ValidationError{
+			Claim:    Audience,
+			Err:      ErrTokenAudienceInvalid,
+			Reason:   fmt.Sprintf("%v does not contain %q", actual, audience),
+			Expected: audience,
+			Actual:   actual,
+			Time:     now,
+		})
+	}
+
+	return errs
+}
+
+// validateRevocation checks the token id against the revocation stores
+func (c Claims) validateRevocation(o *validateOptions, now time.Time) []error {
+	if len(o.revocations) == 0 {
+		return nil
+	}
+
+	tokenID, err := c.GetTokenID()
+	if err != nil {
+		return []error{&ValidationError{Claim: TokenID, Err: err, Reason: "is required to check revocation", Time: now}}
+	}
+
+	for _, store := range o.revocations {
+		revoked, err := store.IsRevoked(tokenID)
+		if err != nil {
+			return []error{err}
+		}
+		if revoked {
+			return []error{&ValidationError{Claim: TokenID, Err: ErrTokenRevoked, Reason: "has been revoked", Actual: tokenID, Time: now}}
+		}
+	}
+
+	return nil
+}
+
+// validateReplay records the token id in the replay cache
+func (c Claims) validateReplay(o *validateOptions, now time.Time) []error {
+	var errs []error
+	for _, name := range []string{TokenID, ExpiresAt} {
+		if !c.Has(name) {
+			errs = append(errs, &ValidationError{Claim: name, Err: ErrNotFound, Reason: "is required to check replay", Time: now})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Tokens are accepted until exp plus the leeway, record them as long
+	err := o.replay.UseClaims(c, WithLeeway(o.leeway))
+	if errors.Is(err, ErrTokenReplayed) {
+		tokenID, _ := c.GetTokenID()
+		return []error{&ValidationError{Claim: TokenID, Err: err, Reason: "has already been used", Actual: tokenID, Time: now}}
+	}
+	if err != nil {
+		return []error{err}
+	}
+
 	return nil
 }
@@ -0,0 +1,186 @@
+package sjwt
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// GetAs will get the claim value converted to type T.
+// Numbers are widened or narrowed when no precision is lost, time.Time is read
+// from unix timestamps or RFC 3339 strings and everything else, such as the
+// []any and map[string]any values produced by Parse, is converted through json
+func GetAs[T any](c Claims, name string) (T, error) {
+	var out T
+	if !c.Has(name) {
+		return out, ErrNotFound
+	}
+
+	if val, ok := c[name].(T); ok {
+		return val, nil
+	}
+
+	if err := convertValue(c[name], &out); err != nil {
+		return out, ErrClaimValueInvalid
+	}
+
+	return out, nil
+}
+
+// convertValue sets dst, a pointer, to the converted value of src
+func convertValue(src any, dst any) error {
+	if src == nil {
+		return ErrClaimValueInvalid
+	}
+
+	out := reflect.ValueOf(dst).Elem()
+	if out.Type() == timeType {
+		t, err := toTime(src)
+		if err != nil {
+			return err
+		}
+		out.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if isNumericKind(out.Kind()) {
+		switch val := src.(type) {
+		case json.Number:
+			return convertJSONNumber(val, out)
+		case NumericDate:
+			src = val.Seconds()
+			if val.Nanosecond() == 0 {
+				src = val.Unix()
+			}
+		}
+		return convertNumber(reflect.ValueOf(src), out)
+	}
+
+	srcBytes, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(srcBytes, dst)
+}
+
+// convertNumber sets out to the numeric value in, failing on overflow or lost fractions
+func convertNumber(in reflect.Value, out reflect.Value) error {
+	switch {
+	case isIntKind(in.Kind()):
+		i := in.Int()
+		switch {
+		case isIntKind(out.Kind()) && !out.OverflowInt(i):
+			out.SetInt(i)
+			return nil
+		case isUintKind(out.Kind()) && i >= 0 && !out.OverflowUint(uint64(i)):
+			out.SetUint(uint64(i))
+			return nil
+		case isFloatKind(out.Kind()):
+			out.SetFloat(float64(i))
+			return nil
+		}
+	case isUintKind(in.Kind()):
+		u := in.Uint()
+		switch {
+		case isIntKind(out.Kind()) && u <= math.MaxInt64 && !out.OverflowInt(int64(u)):
+			out.SetInt(int64(u))
+			return nil
+		case isUintKind(out.Kind()) && !out.OverflowUint(u):
+			out.SetUint(u)
+			return nil
+		case isFloatKind(out.Kind()):
+			out.SetFloat(float64(u))
+			return nil
+		}
+	case isFloatKind(in.Kind()):
+		f := in.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return ErrClaimValueInvalid
+		}
+		switch {
+		case isFloatKind(out.Kind()) && !out.OverflowFloat(f):
+			out.SetFloat(f)
+			return nil
+		case f != math.Trunc(f):
+			return ErrClaimValueInvalid
+		case isIntKind(out.Kind()) && f >= math.MinInt64 && f < math.MaxInt64 && !out.OverflowInt(int64(f)):
+			out.SetInt(int64(f))
+			return nil
+		case isUintKind(out.Kind()) && f >= 0 && f < math.MaxUint64 && !out.OverflowUint(uint64(f)):
+			out.SetUint(uint64(f))
+			return nil
+		}
+	}
+
+	return ErrClaimValueInvalid
+}
+
+// convertJSONNumber sets out to the exact value of n when it is an integer
+func convertJSONNumber(n json.Number, out reflect.Value) error {
+	if isIntKind(out.Kind()) {
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			return convertNumber(reflect.ValueOf(i), out)
+		}
+	}
+	if isUintKind(out.Kind()) {
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return convertNumber(reflect.ValueOf(u), out)
+		}
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return ErrClaimValueInvalid
+	}
+
+	return convertNumber(reflect.ValueOf(f), out)
+}
+
+// toTime converts unix timestamps, NumericDates and RFC 3339 strings to time
+func toTime(src any) (time.Time, error) {
+	switch val := src.(type) {
+	case time.Time:
+		return val, nil
+	case NumericDate:
+		return val.Time, nil
+	case json.Number:
+		return parseNumericDate(val.String())
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return t, nil
+		}
+		return parseNumericDate(val)
+	}
+
+	var secs float64
+	if err := convertNumber(reflect.ValueOf(src), reflect.ValueOf(&secs).Elem()); err != nil {
+		return time.Time{}, err
+	}
+	if secs < minNumericDate || secs > maxNumericDate {
+		return time.Time{}, ErrClaimValueInvalid
+	}
+	whole, frac := math.Modf(secs)
+
+	return time.Unix(int64(whole), int64(math.Round(frac*1e9))), nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return isIntKind(k) || isUintKind(k) || isFloatKind(k)
+}
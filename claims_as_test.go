@@ -0,0 +1,109 @@
+package sjwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetAs(t *testing.T) {
+	claims := New()
+	claims.Set("string", "hello world")
+	claims.Set("int", 8675309)
+	claims.Set("float", 86753.09)
+	claims.Set("wholefloat", float64(42))
+	claims.Set("negative", -1)
+	claims.Set("big", int64(1)<<40)
+	claims.Set("time", int64(1700000000))
+	claims.Set("fractime", 1700000000.5)
+	claims.Set("rfctime", "2023-11-14T22:13:20Z")
+
+	str, err := GetAs[string](*claims, "string")
+	if err != nil || str != "hello world" {
+		t.Errorf("string claim is incorrect, got: %v %v", str, err)
+	}
+
+	i64, err := GetAs[int64](*claims, "int")
+	if err != nil || i64 != 8675309 {
+		t.Errorf("int claim is incorrect, got: %v %v", i64, err)
+	}
+	f64, err := GetAs[float64](*claims, "int")
+	if err != nil || f64 != 8675309 {
+		t.Errorf("int to float claim is incorrect, got: %v %v", f64, err)
+	}
+	u8, err := GetAs[uint8](*claims, "wholefloat")
+	if err != nil || u8 != 42 {
+		t.Errorf("wholefloat claim is incorrect, got: %v %v", u8, err)
+	}
+	if _, err := GetAs[int](*claims, "float"); !errors.Is(err, ErrClaimValueInvalid) {
+		t.Errorf("fractional float should not convert to int, got: %v", err)
+	}
+	if _, err := GetAs[uint](*claims, "negative"); !errors.Is(err, ErrClaimValueInvalid) {
+		t.Errorf("negative int should not convert to uint, got: %v", err)
+	}
+	if _, err := GetAs[int32](*claims, "big"); !errors.Is(err, ErrClaimValueInvalid) {
+		t.Errorf("big int should overflow int32, got: %v", err)
+	}
+	if _, err := GetAs[bool](*claims, "string"); !errors.Is(err, ErrClaimValueInvalid) {
+		t.Errorf("string should not convert to bool, got: %v", err)
+	}
+	if _, err := GetAs[string](*claims, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing claim should return ErrNotFound, got: %v", err)
+	}
+
+	tm, err := GetAs[time.Time](*claims, "time")
+	if err != nil || !tm.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("time claim is incorrect, got: %v %v", tm, err)
+	}
+	tm, err = GetAs[time.Time](*claims, "fractime")
+	if err != nil || !tm.Equal(time.Unix(1700000000, 5e8)) {
+		t.Errorf("fractime claim is incorrect, got: %v %v", tm, err)
+	}
+	tm, err = GetAs[time.Time](*claims, "rfctime")
+	if err != nil || !tm.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("rfctime claim is incorrect, got: %v %v", tm, err)
+	}
+}
+
+func TestGetAsParsed(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+		Zip  int    `json:"zip"`
+	}
+	type role string
+
+	claims := New()
+	claims.Set("roles", []string{"admin", "editor"})
+	claims.Set("limits", map[string]int{"reads": 10, "writes": 2})
+	claims.Set("address", address{City: "Denver", Zip: 80202})
+	claims.Set("role", "admin")
+
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	parsed, err := Parse(token)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	roles, err := GetAs[[]string](parsed, "roles")
+	if err != nil || len(roles) != 2 || roles[0] != "admin" || roles[1] != "editor" {
+		t.Errorf("roles claim is incorrect, got: %v %v", roles, err)
+	}
+	limits, err := GetAs[map[string]int](parsed, "limits")
+	if err != nil || limits["reads"] != 10 || limits["writes"] != 2 {
+		t.Errorf("limits claim is incorrect, got: %v %v", limits, err)
+	}
+	addr, err := GetAs[address](parsed, "address")
+	if err != nil || addr.City != "Denver" || addr.Zip != 80202 {
+		t.Errorf("address claim is incorrect, got: %v %v", addr, err)
+	}
+	r, err := GetAs[role](parsed, "role")
+	if err != nil || r != "admin" {
+		t.Errorf("role claim is incorrect, got: %v %v", r, err)
+	}
+	if _, err := GetAs[[]int](parsed, "roles"); !errors.Is(err, ErrClaimValueInvalid) {
+		t.Errorf("string slice should not convert to int slice, got: %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package sjwt
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Paths address claims nested inside objects and arrays.
+// A path starting with "/" is a RFC 6901 JSON Pointer such as "/realm_access/roles/0",
+// anything else is a dotted path such as "realm_access.roles.0".
+// Dotted paths prefer the longest matching key so claim names containing dots
+// like "https://example.com/claims.tenant.id" still resolve
+
+// GetPath gets the claim value at path
+func (c Claims) GetPath(path string) (any, error) {
+	if !strings.HasPrefix(path, "/") {
+		val, ok := lookupDotted(c, path)
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return val, nil
+	}
+
+	segments, err := splitPointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var node any = c
+	for _, segment := range segments {
+		next, ok := lookupSegment(node, segment)
+		if !ok {
+			return nil, ErrNotFound
+		}
+		node = next
+	}
+
+	return node, nil
+}
+
+// HasPath will let you know whether or not a claim exists at path
+func (c Claims) HasPath(path string) bool { _, err := c.GetPath(path); return err == nil }
+
+// SetPath sets the claim value at path, creating any missing objects along the way
+func (c Claims) SetPath(path string, value any) error {
+	if !strings.HasPrefix(path, "/") {
+		if path == "" {
+			return ErrClaimPathInvalid
+		}
+		return setDotted(c, path, value)
+	}
+
+	segments, err := splitPointer(path)
+	if err != nil {
+		return err
+	}
+
+	return setSegments(c, segments, value)
+}
+
+// GetPathAs will get the claim value at path converted to type T, see GetAs
+func GetPathAs[T any](c Claims, path string) (T, error) {
+	val, err := c.GetPath(path)
+	if err != nil {
+		var out T
+		return out, err
+	}
+
+	return GetAs[T](Claims{path: val}, path)
+}
+
+// GetPathBool will get the boolean value at path, see GetBool
+func (c Claims) GetPathBool(path string) (bool, error) {
+	val, err := c.GetPath(path)
+	if err != nil {
+		return false, err
+	}
+
+	return Claims{path: val}.GetBool(path)
+}
+
+// GetPathStr will get the string value at path, see GetStr
+func (c Claims) GetPathStr(path string) (string, error) {
+	val, err := c.GetPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	return Claims{path: val}.GetStr(path)
+}
+
+// GetPathInt will get the int value at path, see GetInt
+func (c Claims) GetPathInt(path string) (int, error) {
+	val, err := c.GetPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return Claims{path: val}.GetInt(path)
+}
+
+// GetPathInt64 will get the int64 value at path, see GetInt64
+func (c Claims) GetPathInt64(path string) (int64, error) {
+	val, err := c.GetPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return Claims{path: val}.GetInt64(path)
+}
+
+// GetPathFloat will get the float value at path, see GetFloat
+func (c Claims) GetPathFloat(path string) (float64, error) {
+	val, err := c.GetPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return Claims{path: val}.GetFloat(path)
+}
+
+// splitPointer splits a JSON Pointer into its unescaped reference tokens
+func splitPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, ErrClaimPathInvalid
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		// ~ may only be used in the ~0 and ~1 escapes
+		for j := 0; j < len(segment); j++ {
+			if segment[j] == '~' && (j+1 >= len(segment) || (segment[j+1] != '0' && segment[j+1] != '1')) {
+				return nil, ErrClaimPathInvalid
+			}
+		}
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+
+	return segments, nil
+}
+
+// lookupSegment gets the child of an object or array node
+func lookupSegment(node any, segment string) (any, bool) {
+	switch n := node.(type) {
+	case Claims:
+		val, ok := n[segment]
+		return val, ok
+	case map[string]any:
+		val, ok := n[segment]
+		return val, ok
+	case []any:
+		i, ok := arrayIndex(segment, len(n))
+		if !ok {
+			return nil, false
+		}
+		return n[i], true
+	}
+
+	// Fall back to reflection for typed values set directly on the claims
+	v := reflect.ValueOf(node)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		val := v.MapIndex(reflect.ValueOf(segment).Convert(v.Type().Key()))
+		if !val.IsValid() {
+			return nil, false
+		}
+		return val.Interface(), true
+	case reflect.Slice, reflect.Array:
+		i, ok := arrayIndex(segment, v.Len())
+		if !ok {
+			return nil, false
+		}
+		return v.Index(i).Interface(), true
+	}
+
+	return nil, false
+}
+
+// lookupDotted walks a dotted path trying the longest matching key first
+func lookupDotted(node any, path string) (any, bool) {
+	if val, ok := lookupSegment(node, path); ok {
+		return val, true
+	}
+
+	for i := strings.LastIndexByte(path, '.'); i >= 0; i = strings.LastIndexByte(path[:i], '.') {
+		child, ok := lookupSegment(node, path[:i])
+		if !ok {
+			continue
+		}
+		if val, ok := lookupDotted(child, path[i+1:]); ok {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+// setSegments sets value at the segments below node
+func setSegments(node any, segments []string, value any) error {
+	segment, rest := segments[0], segments[1:]
+	if len(rest) == 0 {
+		return setChild(node, segment, value)
+	}
+
+	child, ok := lookupSegment(node, segment)
+	if !ok {
+		child = map[string]any{}
+		if err := setChild(node, segment, child); err != nil {
+			return err
+		}
+	}
+
+	return setSegments(child, rest, value)
+}
+
+// setDotted sets value at a dotted path below node, descending into the longest matching key first
+func setDotted(node any, path string, value any) error {
+	if _, ok := lookupSegment(node, path); ok {
+		return setChild(node, path, value)
+	}
+
+	for i := strings.LastIndexByte(path, '.'); i >= 0; i = strings.LastIndexByte(path[:i], '.') {
+		child, ok := lookupSegment(node, path[:i])
+		if !ok || !isContainer(child) {
+			continue
+		}
+		return setDotted(child, path[i+1:], value)
+	}
+
+	return setSegments(node, strings.Split(path, "."), value)
+}
+
+// setChild sets the child of an object or array node
+func setChild(node any, segment string, value any) error {
+	switch n := node.(type) {
+	case Claims:
+		n[segment] = value
+		return nil
+	case map[string]any:
+		n[segment] = value
+		return nil
+	case []any:
+		i, ok := arrayIndex(segment, len(n))
+		if !ok {
+			return ErrClaimPathInvalid
+		}
+		n[i] = value
+		return nil
+	}
+
+	return ErrClaimPathInvalid
+}
+
+func isContainer(node any) bool {
+	switch node.(type) {
+	case Claims, map[string]any, []any:
+		return true
+	}
+
+	return false
+}
+
+// arrayIndex parses segment as an index within an array of length
+func arrayIndex(segment string, length int) (int, bool) {
+	if segment == "" || segment[0] < '0' || segment[0] > '9' || (len(segment) > 1 && segment[0] == '0') {
+		return 0, false
+	}
+	i, err := strconv.Atoi(segment)
+	if err != nil || i < 0 || i >= length {
+		return 0, false
+	}
+
+	return i, true
+}
@@ -0,0 +1,134 @@
+package sjwt
+
+import "testing"
+
+func parsedPathClaims(t *testing.T) Claims {
+	t.Helper()
+
+	claims := New()
+	claims.Set("realm_access", map[string]any{
+		"roles": []string{"admin", "user"},
+		"level": 3,
+	})
+	claims.Set("https://example.com/claims", map[string]any{
+		"tenant": map[string]any{"id": "acme"},
+	})
+	claims.Set("a/b", map[string]any{"m~n": true})
+
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	parsed, err := Parse(token)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	return parsed
+}
+
+func TestGetPath(t *testing.T) {
+	claims := parsedPathClaims(t)
+
+	role, err := claims.GetPathStr("/realm_access/roles/0")
+	if err != nil || role != "admin" {
+		t.Errorf("pointer role is incorrect, got: %v %v", role, err)
+	}
+	role, err = claims.GetPathStr("realm_access.roles.1")
+	if err != nil || role != "user" {
+		t.Errorf("dotted role is incorrect, got: %v %v", role, err)
+	}
+	roles, err := GetPathAs[[]string](claims, "realm_access.roles")
+	if err != nil || len(roles) != 2 {
+		t.Errorf("roles are incorrect, got: %v %v", roles, err)
+	}
+	level, err := claims.GetPathInt("/realm_access/level")
+	if err != nil || level != 3 {
+		t.Errorf("level is incorrect, got: %v %v", level, err)
+	}
+	level64, err := claims.GetPathInt64("realm_access.level")
+	if err != nil || level64 != 3 {
+		t.Errorf("level64 is incorrect, got: %v %v", level64, err)
+	}
+	levelFloat, err := claims.GetPathFloat("realm_access.level")
+	if err != nil || levelFloat != 3 {
+		t.Errorf("level float is incorrect, got: %v %v", levelFloat, err)
+	}
+
+	tenant, err := claims.GetPathStr("https://example.com/claims.tenant.id")
+	if err != nil || tenant != "acme" {
+		t.Errorf("dotted tenant is incorrect, got: %v %v", tenant, err)
+	}
+	tenant, err = claims.GetPathStr("/https:~1~1example.com~1claims/tenant/id")
+	if err != nil || tenant != "acme" {
+		t.Errorf("pointer tenant is incorrect, got: %v %v", tenant, err)
+	}
+	escaped, err := claims.GetPathBool("/a~1b/m~0n")
+	if err != nil || !escaped {
+		t.Errorf("escaped pointer is incorrect, got: %v %v", escaped, err)
+	}
+
+	if _, err := claims.GetPath("/realm_access/roles/2"); err != ErrNotFound {
+		t.Errorf("out of range index should return ErrNotFound, got: %v", err)
+	}
+	if _, err := claims.GetPath("/realm_access/roles/01"); err != ErrNotFound {
+		t.Errorf("leading zero index should return ErrNotFound, got: %v", err)
+	}
+	if _, err := claims.GetPath("realm_access.missing"); err != ErrNotFound {
+		t.Errorf("missing claim should return ErrNotFound, got: %v", err)
+	}
+	if _, err := claims.GetPath("/a~2b"); err != ErrClaimPathInvalid {
+		t.Errorf("invalid escape should return ErrClaimPathInvalid, got: %v", err)
+	}
+	if !claims.HasPath("realm_access.roles") || claims.HasPath("realm_access.groups") {
+		t.Error("HasPath returned incorrect result")
+	}
+}
+
+func TestGetPathTyped(t *testing.T) {
+	claims := New()
+	claims.Set("org", map[string][]string{"teams": {"red", "blue"}})
+
+	team, err := claims.GetPathStr("org.teams.1")
+	if err != nil || team != "blue" {
+		t.Errorf("typed team is incorrect, got: %v %v", team, err)
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	claims := parsedPathClaims(t)
+
+	if err := claims.SetPath("/realm_access/roles/1", "editor"); err != nil {
+		t.Fatalf("SetPath returned error: %v", err)
+	}
+	role, _ := claims.GetPathStr("realm_access.roles.1")
+	if role != "editor" {
+		t.Errorf("role was not set, got: %v", role)
+	}
+
+	if err := claims.SetPath("https://example.com/claims.tenant.name", "Acme"); err != nil {
+		t.Fatalf("SetPath returned error: %v", err)
+	}
+	name, _ := claims.GetPathStr("/https:~1~1example.com~1claims/tenant/name")
+	if name != "Acme" {
+		t.Errorf("tenant name was not set, got: %v", name)
+	}
+
+	if err := claims.SetPath("profile.address.city", "Denver"); err != nil {
+		t.Fatalf("SetPath returned error: %v", err)
+	}
+	city, _ := claims.GetPathStr("/profile/address/city")
+	if city != "Denver" {
+		t.Errorf("city was not set, got: %v", city)
+	}
+
+	if err := claims.SetPath("/realm_access/roles/5", "x"); err != ErrClaimPathInvalid {
+		t.Errorf("out of range index should return ErrClaimPathInvalid, got: %v", err)
+	}
+	if err := claims.SetPath("/realm_access/level/x", "x"); err != ErrClaimPathInvalid {
+		t.Errorf("setting under a number should return ErrClaimPathInvalid, got: %v", err)
+	}
+	if err := claims.SetPath("", "x"); err != ErrClaimPathInvalid {
+		t.Errorf("empty path should return ErrClaimPathInvalid, got: %v", err)
+	}
+}
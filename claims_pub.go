@@ -1,7 +1,9 @@
 package sjwt
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -52,6 +54,10 @@ func (c Claims) GetStr(name string) (string, error) {
 		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
 	case float64:
 		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case json.Number:
+		return val.String(), nil
+	case NumericDate:
+		return val.String(), nil
 	}
 
 	return fmt.Sprintf("%v", c[name]), nil
@@ -59,6 +65,16 @@ func (c Claims) GetStr(name string) (string, error) {
 
 // GetInt will get the int value on the Claims
 func (c Claims) GetInt(name string) (int, error) {
+	v, err := c.GetInt64(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(v), nil
+}
+
+// GetInt64 will get the int64 value on the Claims
+func (c Claims) GetInt64(name string) (int64, error) {
 	if !c.Has(name) {
 		return 0, ErrNotFound
 	}
@@ -69,36 +85,102 @@ func (c Claims) GetInt(name string) (int, error) {
 		if err != nil {
 			return 0, ErrClaimValueInvalid
 		}
-		return int(v), nil
+		return v, nil
+	case json.Number:
+		if v, err := val.Int64(); err == nil {
+			return v, nil
+		}
+		v, err := val.Float64()
+		if err != nil || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, ErrClaimValueInvalid
+		}
+		return int64(v), nil
 	case float32:
-		return int(val), nil
+		return int64(val), nil
 	case float64:
-		return int(val), nil
+		return int64(val), nil
 	case uint:
-		return int(val), nil
+		return int64(val), nil
 	case uint8:
-		return int(val), nil
+		return int64(val), nil
 	case uint16:
-		return int(val), nil
+		return int64(val), nil
 	case uint32:
-		return int(val), nil
+		return int64(val), nil
 	case uint64:
-		return int(val), nil
+		if val > math.MaxInt64 {
+			return 0, ErrClaimValueInvalid
+		}
+		return int64(val), nil
 	case int:
-		return int(val), nil
+		return int64(val), nil
 	case int8:
-		return int(val), nil
+		return int64(val), nil
 	case int16:
-		return int(val), nil
+		return int64(val), nil
 	case int32:
-		return int(val), nil
+		return int64(val), nil
 	case int64:
-		return int(val), nil
+		return val, nil
+	case NumericDate:
+		return val.Unix(), nil
 	}
 
 	return 0, ErrClaimValueInvalid
 }
 
+// GetUint64 will get the uint64 value on the Claims
+func (c Claims) GetUint64(name string) (uint64, error) {
+	if !c.Has(name) {
+		return 0, ErrNotFound
+	}
+
+	switch val := c[name].(type) {
+	case string:
+		v, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return 0, ErrClaimValueInvalid
+		}
+		return v, nil
+	case json.Number:
+		if v, err := strconv.ParseUint(string(val), 10, 64); err == nil {
+			return v, nil
+		}
+		v, err := val.Float64()
+		if err != nil || v < 0 || v >= math.MaxUint64 {
+			return 0, ErrClaimValueInvalid
+		}
+		return uint64(v), nil
+	case float32:
+		if val < 0 {
+			return 0, ErrClaimValueInvalid
+		}
+		return uint64(val), nil
+	case float64:
+		if val < 0 {
+			return 0, ErrClaimValueInvalid
+		}
+		return uint64(val), nil
+	case uint:
+		return uint64(val), nil
+	case uint8:
+		return uint64(val), nil
+	case uint16:
+		return uint64(val), nil
+	case uint32:
+		return uint64(val), nil
+	case uint64:
+		return val, nil
+	}
+
+	v, err := c.GetInt64(name)
+	if err != nil || v < 0 {
+		return 0, ErrClaimValueInvalid
+	}
+
+	return uint64(v), nil
+}
+
 // GetFloat will get the float value on the Claims
 func (c Claims) GetFloat(name string) (float64, error) {
 	if !c.Has(name) {
@@ -110,9 +192,17 @@ func (c Claims) GetFloat(name string) (float64, error) {
 		return float64(val), nil
 	case float64:
 		return float64(val), nil
+	case json.Number:
+		v, err := val.Float64()
+		if err != nil {
+			return 0, ErrClaimValueInvalid
+		}
+		return v, nil
 	case string:
 		v, _ := strconv.ParseFloat(val, 64)
 		return v, nil
+	case NumericDate:
+		return val.Seconds(), nil
 	}
 
 	return 0, ErrClaimValueInvalid
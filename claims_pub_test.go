@@ -1,6 +1,9 @@
 package sjwt
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestClaims(t *testing.T) {
 	claims := New()
@@ -91,3 +94,47 @@ func TestClaims(t *testing.T) {
 		t.Error("stringfloat claim is incorrect, got: ", stringfloat)
 	}
 }
+
+func TestClaimsInt64(t *testing.T) {
+	claims := New()
+	claims.Set("number", json.Number("9007199254740993"))
+	claims.Set("fraction", json.Number("42.75"))
+	claims.Set("negative", -5)
+	claims.Set("maxuint", uint64(18446744073709551615))
+	claims.Set("string", "9007199254740993")
+
+	number, err := claims.GetInt64("number")
+	if err != nil || number != 9007199254740993 {
+		t.Error("number claim is incorrect, got: ", number, err)
+	}
+	fraction, err := claims.GetInt64("fraction")
+	if err != nil || fraction != 42 {
+		t.Error("fraction claim is incorrect, got: ", fraction, err)
+	}
+	fractionFloat, err := claims.GetFloat("fraction")
+	if err != nil || fractionFloat != 42.75 {
+		t.Error("fraction float claim is incorrect, got: ", fractionFloat, err)
+	}
+	str, err := claims.GetInt64("string")
+	if err != nil || str != 9007199254740993 {
+		t.Error("string claim is incorrect, got: ", str, err)
+	}
+	if _, err := claims.GetInt64("maxuint"); err != ErrClaimValueInvalid {
+		t.Error("maxuint should overflow int64, got: ", err)
+	}
+
+	maxuint, err := claims.GetUint64("maxuint")
+	if err != nil || maxuint != 18446744073709551615 {
+		t.Error("maxuint claim is incorrect, got: ", maxuint, err)
+	}
+	unumber, err := claims.GetUint64("number")
+	if err != nil || unumber != 9007199254740993 {
+		t.Error("unsigned number claim is incorrect, got: ", unumber, err)
+	}
+	if _, err := claims.GetUint64("negative"); err != ErrClaimValueInvalid {
+		t.Error("negative should not convert to uint64, got: ", err)
+	}
+	if _, err := claims.GetUint64("missing"); err != ErrNotFound {
+		t.Error("missing should return ErrNotFound, got: ", err)
+	}
+}
@@ -1,6 +1,9 @@
 package sjwt
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 const (
 	// TokenID is a unique identifier for this token
@@ -71,18 +74,18 @@ func (c Claims) SetAudience(audience []string) { c[Audience] = audience }
 // DeleteAudience deletes audience
 func (c Claims) DeleteAudience() { delete(c, Audience) }
 
-// GetAudience will get the audience set on the Claims
-func (c Claims) GetAudience() ([]string, error) {
-	if !c.Has(Audience) {
-		return []string{}, ErrNotFound
-	}
+// GetAudience will get the audience set on the Claims.
+// Both the single string and the array form allowed by RFC 7519 are accepted
+func (c Claims) GetAudience() ([]string, error) { return c.getStrings(Audience) }
 
-	switch val := c[Audience].(type) {
-	case []string:
-		return val, nil
+// HasAudience will let you know whether or not the audience contains aud
+func (c Claims) HasAudience(aud string) bool {
+	audience, err := c.GetAudience()
+	if err != nil {
+		return false
 	}
 
-	return []string{}, ErrClaimValueInvalid
+	return slices.Contains(audience, aud)
 }
 
 // SetSubject will set a subject value
@@ -105,62 +108,93 @@ func (c Claims) GetSubject() (string, error) {
 	return "", ErrClaimValueInvalid
 }
 
-// SetIssuedAt will set an issued at timestamp in nanoseconds
+// SetIssuedAt will set an issued at unix timestamp in whole seconds,
+// use Set(IssuedAt, NewNumericDate(t)) to keep sub-second precision
 func (c Claims) SetIssuedAt(issuedAt time.Time) { c[IssuedAt] = issuedAt.Unix() }
 
 // DeleteIssuedAt deletes issued at
 func (c Claims) DeleteIssuedAt() { delete(c, IssuedAt) }
 
-// GetIssuedAt will get the issued at timestamp set on the Claims
+// GetIssuedAt will get the issued at unix timestamp set on the Claims
 func (c Claims) GetIssuedAt() (int64, error) {
-	if !c.Has(IssuedAt) {
-		return 0, ErrNotFound
-	}
-
-	issuedAt, err := c.GetInt(IssuedAt)
+	issuedAt, err := c.GetIssuedAtTime()
 	if err != nil {
-		return 0, ErrClaimValueInvalid
+		return 0, err
 	}
 
-	return int64(issuedAt), nil
+	return issuedAt.Unix(), nil
 }
 
-// SetExpiresAt will set an expires at timestamp in nanoseconds
+// GetIssuedAtTime will get the issued at time set on the Claims
+func (c Claims) GetIssuedAtTime() (time.Time, error) { return c.getTime(IssuedAt) }
+
+// SetExpiresAt will set an expires at unix timestamp in whole seconds,
+// use Set(ExpiresAt, NewNumericDate(t)) to keep sub-second precision
 func (c Claims) SetExpiresAt(expiresAt time.Time) { c[ExpiresAt] = expiresAt.Unix() }
 
+// SetExpiresIn will set expires at to the current time plus the duration
+func (c Claims) SetExpiresIn(duration time.Duration) { c.SetExpiresAt(time.Now().Add(duration)) }
+
 // DeleteExpiresAt deletes expires at
 func (c Claims) DeleteExpiresAt() { delete(c, ExpiresAt) }
 
-// GetExpiresAt will get the expires at timestamp set on the Claims
+// GetExpiresAt will get the expires at unix timestamp set on the Claims
 func (c Claims) GetExpiresAt() (int64, error) {
-	if !c.Has(ExpiresAt) {
-		return 0, ErrNotFound
+	expiresAt, err := c.GetExpiresAtTime()
+	if err != nil {
+		return 0, err
 	}
 
-	expiresAt, err := c.GetInt(ExpiresAt)
+	return expiresAt.Unix(), nil
+}
+
+// GetExpiresAtTime will get the expires at time set on the Claims
+func (c Claims) GetExpiresAtTime() (time.Time, error) { return c.getTime(ExpiresAt) }
+
+// TimeUntilExpiry will get the duration until the token expires, negative once expired
+func (c Claims) TimeUntilExpiry() (time.Duration, error) {
+	expiresAt, err := c.GetExpiresAtTime()
 	if err != nil {
-		return 0, ErrClaimValueInvalid
+		return 0, err
 	}
 
-	return int64(expiresAt), nil
+	return time.Until(expiresAt), nil
 }
 
-// SetNotBeforeAt will set an not before at timestamp in nanoseconds
+// SetNotBeforeAt will set a not before at unix timestamp in whole seconds,
+// use Set(NotBeforeAt, NewNumericDate(t)) to keep sub-second precision
 func (c Claims) SetNotBeforeAt(notbeforeAt time.Time) { c[NotBeforeAt] = notbeforeAt.Unix() }
 
 // DeleteNotBeforeAt deletes not before at
 func (c Claims) DeleteNotBeforeAt() { delete(c, NotBeforeAt) }
 
-// GetNotBeforeAt will get the not before at timestamp set on the Claims
+// GetNotBeforeAt will get the not before at unix timestamp set on the Claims
 func (c Claims) GetNotBeforeAt() (int64, error) {
-	if !c.Has(NotBeforeAt) {
-		return 0, ErrNotFound
+	notBeforeAt, err := c.GetNotBeforeAtTime()
+	if err != nil {
+		return 0, err
+	}
+
+	return notBeforeAt.Unix(), nil
+}
+
+// GetNotBeforeAtTime will get the not before at time set on the Claims
+func (c Claims) GetNotBeforeAtTime() (time.Time, error) { return c.getTime(NotBeforeAt) }
+
+// getTime will get a NumericDate claim as time.
+// RFC 7519 NumericDates are json numbers so string values are invalid
+func (c Claims) getTime(name string) (time.Time, error) {
+	if !c.Has(name) {
+		return time.Time{}, ErrNotFound
+	}
+	if _, ok := c[name].(string); ok {
+		return time.Time{}, ErrClaimValueInvalid
 	}
 
-	notBeforeAt, err := c.GetInt(NotBeforeAt)
+	t, err := toTime(c[name])
 	if err != nil {
-		return 0, ErrClaimValueInvalid
+		return time.Time{}, ErrClaimValueInvalid
 	}
 
-	return int64(notBeforeAt), nil
+	return t, nil
 }
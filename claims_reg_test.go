@@ -54,6 +54,41 @@ func TestAudience(t *testing.T) {
 	if len(audience) != 0 {
 		t.Error("should have gotten empty string array")
 	}
+
+	claims.Set(Audience, "Google")
+	audience, _ = claims.GetAudience()
+	if len(audience) != 1 || audience[0] != "Google" {
+		t.Error("single string audience was not read")
+	}
+	if !claims.HasAudience("Google") || claims.HasAudience("Facebook") {
+		t.Error("single string audience has incorrect membership")
+	}
+}
+
+func TestAudienceParsed(t *testing.T) {
+	claims := New()
+	claims.SetAudience([]string{"Google", "Facebook"})
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	parsed, err := Parse(token)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	audience, err := parsed.GetAudience()
+	if err != nil || len(audience) != 2 || audience[0] != "Google" || audience[1] != "Facebook" {
+		t.Errorf("parsed audience is incorrect, got: %v %v", audience, err)
+	}
+	if !parsed.HasAudience("Facebook") || parsed.HasAudience("Twitter") {
+		t.Error("parsed audience has incorrect membership")
+	}
+
+	parsed.Set(Audience, []any{"Google", 42})
+	if _, err := parsed.GetAudience(); err != ErrClaimValueInvalid {
+		t.Errorf("mixed audience should be invalid, got: %v", err)
+	}
 }
 
 func TestSubject(t *testing.T) {
@@ -125,3 +160,77 @@ func TestNotBeforeAt(t *testing.T) {
 		t.Error("should have gotten 0 value")
 	}
 }
+
+func TestTimeAccessors(t *testing.T) {
+	now := time.Unix(1700000000, 250000000)
+	claims := New()
+	claims.Set(IssuedAt, NewNumericDate(now))
+	claims.Set(NotBeforeAt, NewNumericDate(now))
+	claims.Set(ExpiresAt, NewNumericDate(now.Add(time.Hour)))
+
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	parsed, err := Parse(token)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	issuedAt, err := parsed.GetIssuedAtTime()
+	if err != nil || !issuedAt.Equal(now) {
+		t.Errorf("issuedAt time is incorrect, got: %v %v", issuedAt, err)
+	}
+	notBeforeAt, err := parsed.GetNotBeforeAtTime()
+	if err != nil || !notBeforeAt.Equal(now) {
+		t.Errorf("notBeforeAt time is incorrect, got: %v %v", notBeforeAt, err)
+	}
+	expiresAt, err := parsed.GetExpiresAtTime()
+	if err != nil || !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expiresAt time is incorrect, got: %v %v", expiresAt, err)
+	}
+	expiresAtUnix, _ := parsed.GetExpiresAt()
+	if expiresAtUnix != now.Add(time.Hour).Unix() {
+		t.Errorf("expiresAt unix is incorrect, got: %v", expiresAtUnix)
+	}
+
+	// The setters keep whole seconds so the claims fit int64 fields
+	type registered struct {
+		IssuedAt    int64 `json:"iat"`
+		NotBeforeAt int64 `json:"nbf"`
+		ExpiresAt   int64 `json:"exp"`
+	}
+	claims.SetIssuedAt(now)
+	claims.SetNotBeforeAt(now)
+	claims.SetExpiresAt(now.Add(time.Hour))
+	token, _ = claims.Generate(secretKey)
+	parsed, _ = Parse(token)
+	var reg registered
+	if err := parsed.ToStruct(&reg); err != nil || reg.IssuedAt != now.Unix() || reg.NotBeforeAt != now.Unix() || reg.ExpiresAt != now.Add(time.Hour).Unix() {
+		t.Errorf("setters should store whole seconds, got: %+v %v", reg, err)
+	}
+
+	parsed.Set(ExpiresAt, "soon")
+	if _, err := parsed.GetExpiresAtTime(); err != ErrClaimValueInvalid {
+		t.Errorf("expected ErrClaimValueInvalid, got %v", err)
+	}
+}
+
+func TestExpiresIn(t *testing.T) {
+	claims := New()
+	if _, err := claims.TimeUntilExpiry(); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	claims.SetExpiresIn(time.Hour)
+	until, err := claims.TimeUntilExpiry()
+	if err != nil || until <= 59*time.Minute || until > time.Hour {
+		t.Errorf("time until expiry is incorrect, got: %v %v", until, err)
+	}
+
+	claims.SetExpiresIn(-time.Minute)
+	until, _ = claims.TimeUntilExpiry()
+	if until >= 0 {
+		t.Errorf("expired token should have negative time until expiry, got: %v", until)
+	}
+}
@@ -0,0 +1,118 @@
+package sjwt
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// Scope is the OAuth 2.0 space separated list of scopes granted to the token
+	Scope = "scope"
+
+	// Roles is the list of roles granted to the subject
+	Roles = "roles"
+
+	// Groups is the list of groups the subject belongs to
+	Groups = "groups"
+
+	// Entitlements is the list of individual resources the subject is entitled to
+	Entitlements = "entitlements"
+)
+
+// SetScopes will set the scopes as a space separated string
+func (c Claims) SetScopes(scopes []string) { c[Scope] = strings.Join(scopes, " ") }
+
+// GetScopes will get the scopes set on the Claims.
+// Both the space separated string and the array form are accepted
+func (c Claims) GetScopes() ([]string, error) {
+	if scope, ok := c[Scope].(string); ok {
+		return strings.Fields(scope), nil
+	}
+
+	return c.getStrings(Scope)
+}
+
+// HasScope will let you know whether or not the token was granted the scope
+func (c Claims) HasScope(scope string) bool { return c.HasAllScopes(scope) }
+
+// HasAllScopes will let you know whether or not the token was granted every scope
+func (c Claims) HasAllScopes(scopes ...string) bool {
+	granted, err := c.GetScopes()
+	if err != nil {
+		return false
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasAnyScope will let you know whether or not the token was granted at least one of the scopes
+func (c Claims) HasAnyScope(scopes ...string) bool {
+	granted, err := c.GetScopes()
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(scopes, func(scope string) bool { return slices.Contains(granted, scope) })
+}
+
+// SetRoles will set the roles
+func (c Claims) SetRoles(roles []string) { c[Roles] = roles }
+
+// GetRoles will get the roles set on the Claims
+func (c Claims) GetRoles() ([]string, error) { return c.getStrings(Roles) }
+
+// HasRole will let you know whether or not the subject has the role
+func (c Claims) HasRole(role string) bool {
+	roles, err := c.GetRoles()
+	return err == nil && slices.Contains(roles, role)
+}
+
+// SetGroups will set the groups
+func (c Claims) SetGroups(groups []string) { c[Groups] = groups }
+
+// GetGroups will get the groups set on the Claims
+func (c Claims) GetGroups() ([]string, error) { return c.getStrings(Groups) }
+
+// HasGroup will let you know whether or not the subject belongs to the group
+func (c Claims) HasGroup(group string) bool {
+	groups, err := c.GetGroups()
+	return err == nil && slices.Contains(groups, group)
+}
+
+// SetEntitlements will set the entitlements
+func (c Claims) SetEntitlements(entitlements []string) { c[Entitlements] = entitlements }
+
+// GetEntitlements will get the entitlements set on the Claims
+func (c Claims) GetEntitlements() ([]string, error) { return c.getStrings(Entitlements) }
+
+// getStrings will get a string or list of strings claim, such as the []any values produced by Parse
+func (c Claims) getStrings(name string) ([]string, error) {
+	if !c.Has(name) {
+		return []string{}, ErrNotFound
+	}
+
+	switch val := c[name].(type) {
+	case string:
+		return []string{val}, nil
+	case []string:
+		return val, nil
+	case []any:
+		list := make([]string, 0, len(val))
+		for _, v := range val {
+			s, ok := v.(string)
+			if !ok {
+				return []string{}, ErrClaimValueInvalid
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	}
+
+	return []string{}, ErrClaimValueInvalid
+}
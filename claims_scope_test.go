@@ -0,0 +1,69 @@
+package sjwt
+
+import "testing"
+
+func TestScopes(t *testing.T) {
+	claims := New()
+	if _, err := claims.GetScopes(); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if claims.HasScope("orders:read") {
+		t.Error("claims without scope should not have any scope")
+	}
+
+	claims.SetScopes([]string{"orders:read", "orders:write"})
+	scope, _ := claims.GetStr(Scope)
+	if scope != "orders:read orders:write" {
+		t.Errorf("scope should be space separated, got %s", scope)
+	}
+
+	token, _ := claims.Generate(secretKey)
+	parsed, _ := Parse(token)
+	scopes, err := parsed.GetScopes()
+	if err != nil || len(scopes) != 2 {
+		t.Errorf("scopes are incorrect, got: %v %v", scopes, err)
+	}
+	if !parsed.HasScope("orders:write") || parsed.HasScope("orders:delete") {
+		t.Error("HasScope returned incorrect result")
+	}
+	if !parsed.HasAllScopes("orders:read", "orders:write") || parsed.HasAllScopes("orders:read", "orders:delete") {
+		t.Error("HasAllScopes returned incorrect result")
+	}
+	if !parsed.HasAnyScope("orders:delete", "orders:read") || parsed.HasAnyScope("orders:delete") {
+		t.Error("HasAnyScope returned incorrect result")
+	}
+
+	// Array form used by some providers
+	parsed.Set(Scope, []any{"profile", "email"})
+	if !parsed.HasAllScopes("profile", "email") {
+		t.Error("array scopes were not read")
+	}
+}
+
+func TestRolesAndGroups(t *testing.T) {
+	claims := New()
+	claims.SetRoles([]string{"admin", "editor"})
+	claims.SetGroups([]string{"staff"})
+
+	token, _ := claims.Generate(secretKey)
+	parsed, _ := Parse(token)
+
+	roles, err := parsed.GetRoles()
+	if err != nil || len(roles) != 2 {
+		t.Errorf("roles are incorrect, got: %v %v", roles, err)
+	}
+	if !parsed.HasRole("admin") || parsed.HasRole("owner") {
+		t.Error("HasRole returned incorrect result")
+	}
+	if !parsed.HasGroup("staff") || parsed.HasGroup("contractors") {
+		t.Error("HasGroup returned incorrect result")
+	}
+
+	parsed.Set(Roles, []any{"admin", 1})
+	if _, err := parsed.GetRoles(); err != ErrClaimValueInvalid {
+		t.Errorf("expected ErrClaimValueInvalid, got %v", err)
+	}
+	if _, err := New().GetGroups(); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package sjwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// RegisteredClaims holds the RFC 7519 registered claims.
+// Embed it in your own claims struct and use GenerateStruct and ParseStruct
+//
+//	type UserClaims struct {
+//		sjwt.RegisteredClaims
+//		Name string `json:"name"`
+//	}
+type RegisteredClaims struct {
+	TokenID     string       `json:"jti,omitempty"`
+	Issuer      string       `json:"iss,omitempty"`
+	Audience    ClaimStrings `json:"aud,omitempty"`
+	Subject     string       `json:"sub,omitempty"`
+	IssuedAt    *NumericDate `json:"iat,omitempty"`
+	ExpiresAt   *NumericDate `json:"exp,omitempty"`
+	NotBeforeAt *NumericDate `json:"nbf,omitempty"`
+}
+
+// SetTokenID will set a random id
+func (r *RegisteredClaims) SetTokenID() { r.TokenID = ID() }
+
+// SetIssuedAt will set the issued at time
+func (r *RegisteredClaims) SetIssuedAt(issuedAt time.Time) {
+	d := NewNumericDate(issuedAt)
+	r.IssuedAt = &d
+}
+
+// SetExpiresAt will set the expires at time
+func (r *RegisteredClaims) SetExpiresAt(expiresAt time.Time) {
+	d := NewNumericDate(expiresAt)
+	r.ExpiresAt = &d
+}
+
+// SetExpiresIn will set expires at to the current time plus the duration
+func (r *RegisteredClaims) SetExpiresIn(duration time.Duration) {
+	r.SetExpiresAt(time.Now().Add(duration))
+}
+
+// SetNotBeforeAt will set the not before at time
+func (r *RegisteredClaims) SetNotBeforeAt(notBeforeAt time.Time) {
+	d := NewNumericDate(notBeforeAt)
+	r.NotBeforeAt = &d
+}
+
+// ClaimStrings is a list of strings that reads both the single string
+// and the array form, such as the audience claim
+type ClaimStrings []string
+
+// UnmarshalJSON reads a json string or array of strings, null leaves the list nil
+func (s *ClaimStrings) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) == "null" {
+		*s = nil
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*s = ClaimStrings{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return ErrClaimValueInvalid
+	}
+	*s = list
+
+	return nil
+}
+
+// GenerateStruct takes in a claims struct and a secret and outputs jwt token
+func GenerateStruct[T any](claims T, secret []byte) (string, error) {
+	claimsEnc, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return sign(claimsEnc, secret)
+}
+
+// ParseStruct verifies the token signature, runs the same checks as Claims.Validate
+// with the options and then unmarshals the payload into T
+func ParseStruct[T any](tokenStr string, secret []byte, opts ...ValidateOption) (T, error) {
+	var out T
+
+	claimsByte, err := verifyPayload(tokenStr, secret)
+	if err != nil {
+		return out, err
+	}
+	claims, err := decodeClaims(claimsByte)
+	if err != nil {
+		return out, err
+	}
+	if err := claims.Validate(opts...); err != nil {
+		return out, err
+	}
+
+	if err := json.Unmarshal(claimsByte, &out); err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
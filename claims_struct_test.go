@@ -0,0 +1,103 @@
+package sjwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type userClaims struct {
+	RegisteredClaims
+	Name      string `json:"name"`
+	AccountID int64  `json:"account_id"`
+}
+
+func TestGenerateParseStruct(t *testing.T) {
+	claims := userClaims{Name: "Billy Mister", AccountID: 1234567890123456789}
+	claims.SetTokenID()
+	claims.Issuer = "issuer.example"
+	claims.Audience = ClaimStrings{"api"}
+	claims.Subject = "user:42"
+	claims.SetIssuedAt(time.Now())
+	claims.SetNotBeforeAt(time.Now().Add(-time.Minute))
+	claims.SetExpiresIn(time.Hour)
+
+	token, err := GenerateStruct(claims, secretKey)
+	if err != nil {
+		t.Fatalf("GenerateStruct returned error: %v", err)
+	}
+
+	parsed, err := ParseStruct[userClaims](token, secretKey, WithAudience("api"))
+	if err != nil {
+		t.Fatalf("ParseStruct returned error: %v", err)
+	}
+	if parsed.Name != "Billy Mister" || parsed.AccountID != 1234567890123456789 {
+		t.Errorf("custom claims are incorrect, got: %+v", parsed)
+	}
+	if parsed.TokenID != claims.TokenID || parsed.Subject != "user:42" || parsed.Issuer != "issuer.example" {
+		t.Errorf("registered claims are incorrect, got: %+v", parsed.RegisteredClaims)
+	}
+	if parsed.ExpiresAt == nil || !parsed.ExpiresAt.Equal(claims.ExpiresAt.Time) {
+		t.Errorf("expires at is incorrect, got: %v", parsed.ExpiresAt)
+	}
+
+	// Tokens generated from struct claims can still be read as Claims
+	mapClaims, err := Parse(token)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if !mapClaims.HasAudience("api") {
+		t.Error("audience was not readable from Claims")
+	}
+
+	if _, err := ParseStruct[userClaims](token, secretKey, WithAudience("admin")); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+	if _, err := ParseStruct[userClaims](token, []byte("another-secret-0123456789abcdef01")); err != ErrTokenSignatureInvalid {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+	if _, err := ParseStruct[userClaims]("not_a_jwt", secretKey); err != ErrTokenInvalid {
+		t.Errorf("expected ErrTokenInvalid, got %v", err)
+	}
+}
+
+func TestParseStructExpired(t *testing.T) {
+	claims := userClaims{Name: "Billy Mister"}
+	claims.SetExpiresAt(time.Now().Add(-time.Minute))
+
+	token, err := GenerateStruct(claims, secretKey)
+	if err != nil {
+		t.Fatalf("GenerateStruct returned error: %v", err)
+	}
+	if _, err := ParseStruct[userClaims](token, secretKey); !errors.Is(err, ErrTokenHasExpired) {
+		t.Errorf("expected ErrTokenHasExpired, got %v", err)
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	var single ClaimStrings
+	if err := json.Unmarshal([]byte(`"api"`), &single); err != nil || len(single) != 1 || single[0] != "api" {
+		t.Errorf("single string was not read, got: %v %v", single, err)
+	}
+
+	var list ClaimStrings
+	if err := json.Unmarshal([]byte(`["api","web"]`), &list); err != nil || len(list) != 2 {
+		t.Errorf("string array was not read, got: %v %v", list, err)
+	}
+
+	// Null is an absent audience, not an empty one
+	var claims RegisteredClaims
+	if err := json.Unmarshal([]byte(`{"aud":null}`), &claims); err != nil || claims.Audience != nil {
+		t.Errorf("null should leave the list nil, got: %q %v", claims.Audience, err)
+	}
+	list = ClaimStrings{"api"}
+	if err := json.Unmarshal([]byte(`null`), &list); err != nil || list != nil {
+		t.Errorf("null should reset the list, got: %q %v", list, err)
+	}
+
+	var invalid ClaimStrings
+	if err := json.Unmarshal([]byte(`42`), &invalid); err != ErrClaimValueInvalid {
+		t.Errorf("expected ErrClaimValueInvalid, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package sjwt
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -82,7 +83,7 @@ func TestValidateExp(t *testing.T) {
 	// Error
 	claims.SetExpiresAt(time.Now().Add(time.Hour * -1))
 	err = claims.Validate()
-	if err != ErrTokenHasExpired {
+	if !errors.Is(err, ErrTokenHasExpired) {
 		t.Error("Token should have expired")
 	}
 }
@@ -99,7 +100,63 @@ func TestValidateNotBefore(t *testing.T) {
 	// Error
 	claims.SetNotBeforeAt(time.Now().Add(time.Hour))
 	err = claims.Validate()
-	if err != ErrTokenNotYetValid {
+	if !errors.Is(err, ErrTokenNotYetValid) {
 		t.Error("Token should have failed due to token not being valid yet")
 	}
 }
+
+func TestValidateAudience(t *testing.T) {
+	claims := New()
+	claims.SetAudience([]string{"api", "billing"})
+	if err := claims.Validate(WithAudience("billing")); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+	if err := claims.Validate(WithAudience("admin", "api")); err != nil {
+		t.Errorf("Validate should accept any expected audience: %v", err)
+	}
+	if err := claims.Validate(WithAudience("admin")); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+
+	claims.DeleteAudience()
+	if err := claims.Validate(WithAudience("api")); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("missing audience should fail, got %v", err)
+	}
+}
+
+func TestValidateErrorDetails(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+
+	claims := New()
+	claims.SetExpiresAt(now.Add(-42 * time.Second))
+	err := claims.Validate(WithClock(clock))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if validationErr.Claim != ExpiresAt || validationErr.Reason != "expired 42s ago" {
+		t.Errorf("validation error details are incorrect, got: %+v", validationErr)
+	}
+	if !validationErr.Time.Equal(now) || !validationErr.Expected.(time.Time).Equal(now.Add(-42*time.Second)) {
+		t.Errorf("validation error times are incorrect, got: %+v", validationErr)
+	}
+	if err.Error() != `token has expired: claim "exp" expired 42s ago` {
+		t.Errorf("validation error message is incorrect, got: %s", err)
+	}
+
+	// Multiple failures are joined
+	claims.SetNotBeforeAt(now.Add(5 * time.Minute))
+	claims.SetAudience([]string{"web"})
+	err = claims.Validate(WithClock(clock), WithAudience("api"))
+	for _, want := range []error{ErrTokenHasExpired, ErrTokenNotYetValid, ErrTokenAudienceInvalid} {
+		if !errors.Is(err, want) {
+			t.Errorf("expected joined error to contain %v, got %v", want, err)
+		}
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 3 {
+		t.Errorf("expected 3 joined errors, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package sjwt
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// ClientAssertionType is the client_assertion_type form value of RFC 7523 client assertions
+	ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	defaultAssertionTTL         = time.Minute
+	defaultAssertionMaxLifetime = 5 * time.Minute
+)
+
+// NewClientSecretAssertion creates a client_secret_jwt client assertion for the token endpoint,
+// signed with HS256 and the client secret
+func NewClientSecretAssertion(clientID string, tokenEndpoint string, secret []byte) (string, error) {
+	claimsEnc, err := json.Marshal(clientAssertionClaims(clientID, tokenEndpoint))
+	if err != nil {
+		return "", err
+	}
+
+	return sign(claimsEnc, secret)
+}
+
+// NewPrivateKeyAssertion creates a private_key_jwt client assertion for the token endpoint,
+// signed with ES256 and the client P-256 private key
+func NewPrivateKeyAssertion(clientID string, tokenEndpoint string, key *ecdsa.PrivateKey) (string, error) {
+	claimsEnc, err := json.Marshal(clientAssertionClaims(clientID, tokenEndpoint))
+	if err != nil {
+		return "", err
+	}
+
+	return signES256(jwtHeader{Typ: jwtType, Alg: es256Algorithm}, claimsEnc, key)
+}
+
+// clientAssertionClaims sets the issuer and subject to the client id, the audience
+// to the token endpoint, a short expiration and a random token id
+func clientAssertionClaims(clientID string, tokenEndpoint string) Claims {
+	now := time.Now()
+	claims := Claims{}
+	claims.SetIssuer(clientID)
+	claims.SetSubject(clientID)
+	claims.Set(Audience, tokenEndpoint)
+	claims.SetIssuedAt(now)
+	claims.SetExpiresAt(now.Add(defaultAssertionTTL))
+	claims.SetTokenID()
+
+	return claims
+}
+
+// ClientKey is how a registered client signs its assertions
+type ClientKey struct {
+	// Secret verifies client_secret_jwt assertions
+	Secret []byte
+
+	// PublicKey verifies private_key_jwt assertions
+	PublicKey *ecdsa.PublicKey
+}
+
+// ClientKeyStore looks up the keys of registered clients
+type ClientKeyStore interface {
+	// ClientKey returns the key of the client, or ErrClientUnknown when it is not registered
+	ClientKey(clientID string) (ClientKey, error)
+}
+
+// ClientKeys is an in-memory ClientKeyStore mapping client ids to their keys
+type ClientKeys map[string]ClientKey
+
+// ClientKey returns the key of the client
+func (k ClientKeys) ClientKey(clientID string) (ClientKey, error) {
+	key, ok := k[clientID]
+	if !ok {
+		return ClientKey{}, ErrClientUnknown
+	}
+
+	return key, nil
+}
+
+// ClientAssertionValidator authenticates clients with RFC 7523 client assertions on token endpoints
+type ClientAssertionValidator struct {
+	// Keys looks up the key of the client named by the assertion issuer
+	Keys ClientKeyStore
+
+	// Audiences are the identifiers aud must contain one of, such as the token endpoint url and the issuer
+	Audiences []string
+
+	// Replay accepts each assertion jti only once when set
+	Replay *ReplayCache
+
+	// MaxLifetime rejects assertions valid for longer, 5 minutes when zero
+	MaxLifetime time.Duration
+
+	// Options are additional validate options, such as WithLeeway.
+	// Assertion ids are remembered until exp plus the leeway
+	Options []ValidateOption
+}
+
+// NewClientAssertionValidator will initiate a new client assertion validator with the default max lifetime
+func NewClientAssertionValidator(keys ClientKeyStore, replay *ReplayCache, audiences ...string) *ClientAssertionValidator {
+	return &ClientAssertionValidator{
+		Keys:        keys,
+		Audiences:   audiences,
+		Replay:      replay,
+		MaxLifetime: defaultAssertionMaxLifetime,
+	}
+}
+
+// Authenticate verifies the assertion with the key of its client, checks iss, sub, aud, exp and jti
+// and returns the authenticated client id. Read assertionType and assertion from the
+// client_assertion_type and client_assertion form values
+func (v *ClientAssertionValidator) Authenticate(assertionType string, assertion string) (string, error) {
+	if assertionType != ClientAssertionType {
+		return "", ErrTokenInvalid
+	}
+
+	token := splitToken(assertion)
+	if len(token) != tokenSegments {
+		return "", ErrTokenInvalid
+	}
+	header, err := decodeHeader(token[headerSegmentIdx])
+	if err != nil {
+		return "", err
+	}
+	if !typeMatches(header.Typ, jwtType) {
+		return "", ErrTokenHeaderInvalid
+	}
+	claimsByte, err := decodePayload(token[payloadSegmentIdx])
+	if err != nil {
+		return "", err
+	}
+	claims, err := decodeClaims(claimsByte)
+	if err != nil {
+		return "", err
+	}
+
+	// The issuer names the client whose key must have signed the assertion
+	clientID, err := claims.GetIssuer()
+	if err != nil || clientID == "" {
+		return "", &ValidationError{Claim: Issuer, Err: ErrNotFound, Reason: "is required", Time: time.Now()}
+	}
+	key, err := v.Keys.ClientKey(clientID)
+	if err != nil {
+		return "", err
+	}
+
+	// The registered key decides the algorithm so a client secret is never used as a public key
+	switch {
+	case header.Alg == jwtAlgorithm && key.Secret != nil:
+		err = verifySignature(token, key.Secret)
+	case header.Alg == es256Algorithm && key.PublicKey != nil:
+		err = verifyES256(token, key.PublicKey)
+	default:
+		err = ErrTokenAlgorithmMismatch
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := claims.validateClientAssertion(v, clientID); err != nil {
+		return "", err
+	}
+
+	return clientID, nil
+}
+
+// validateClientAssertion checks the assertion claims, the jti is recorded only when every other check passes
+func (c Claims) validateClientAssertion(v *ClientAssertionValidator, clientID string) error {
+	// Without audiences any token endpoint would be accepted
+	if len(v.Audiences) == 0 {
+		return ErrTokenAudienceInvalid
+	}
+
+	if subject, err := c.GetSubject(); err == nil && subject != clientID {
+		return &ValidationError{
+			Claim:    Subject,
+			Err:      ErrClaimValueInvalid,
+			Reason:   fmt.Sprintf("%q must equal the issuer", subject),
+			Expected: clientID,
+			Actual:   subject,
+			Time:     time.Now(),
+		}
+	}
+
+	opts := append([]ValidateOption{WithRequired(Issuer, Subject, Audience, ExpiresAt, TokenID)}, v.Options...)
+	if v.Replay != nil {
+		opts = append(opts, WithReplayCache(v.Replay))
+	}
+	maxLifetime := v.MaxLifetime
+	if maxLifetime == 0 {
+		maxLifetime = defaultAssertionMaxLifetime
+	}
+
+	// Audiences and MaxLifetime are checked apart from the options so options can only add checks
+	return c.validateWith(opts, func(o *validateOptions, now time.Time) []error {
+		var errs []error
+		if c.Has(Audience) && !slices.ContainsFunc(v.Audiences, c.HasAudience) {
+			audience, _ := c.GetAudience()
+			errs = append(errs, &ValidationError{
+				Claim:    Audience,
+				Err:      ErrTokenAudienceInvalid,
+				Reason:   fmt.Sprintf("%v does not contain any of %v", audience, v.Audiences),
+				Expected: v.Audiences,
+				Actual:   audience,
+				Time:     now,
+			})
+		}
+
+		return append(errs, c.validateIssuedAt(&validateOptions{clock: o.clock, maxLifetime: maxLifetime}, now)...)
+	})
+}
@@ -0,0 +1,162 @@
+package sjwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	assertionClientID = "client-123"
+	tokenEndpoint     = "https://as.example.com/token"
+)
+
+func TestClientAssertions(t *testing.T) {
+	key := newP256Key(t)
+	keys := ClientKeys{
+		assertionClientID: {Secret: secretKey},
+		"key-client":      {PublicKey: &key.PublicKey},
+	}
+	validator := NewClientAssertionValidator(keys, NewReplayCache(), tokenEndpoint, "https://as.example.com")
+
+	secretAssertion, err := NewClientSecretAssertion(assertionClientID, tokenEndpoint, secretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, _ := Parse(secretAssertion)
+	issuer, _ := claims.GetIssuer()
+	subject, _ := claims.GetSubject()
+	expiresAt, _ := claims.GetExpiresAtTime()
+	if issuer != assertionClientID || subject != assertionClientID || !claims.HasAudience(tokenEndpoint) || !claims.Has(TokenID) || time.Until(expiresAt) > time.Minute {
+		t.Errorf("unexpected assertion claims %v", claims)
+	}
+
+	keyAssertion, err := NewPrivateKeyAssertion("key-client", tokenEndpoint, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for clientID, assertion := range map[string]string{assertionClientID: secretAssertion, "key-client": keyAssertion} {
+		authenticated, err := validator.Authenticate(ClientAssertionType, assertion)
+		if err != nil || authenticated != clientID {
+			t.Errorf("expected %s to authenticate, got %s %v", clientID, authenticated, err)
+		}
+
+		// Each assertion is only accepted once
+		if _, err := validator.Authenticate(ClientAssertionType, assertion); !errors.Is(err, ErrTokenReplayed) {
+			t.Errorf("expected ErrTokenReplayed, got %v", err)
+		}
+	}
+}
+
+func TestClientAssertionFailures(t *testing.T) {
+	key := newP256Key(t)
+	keys := ClientKeys{
+		assertionClientID: {Secret: secretKey},
+		"key-client":      {PublicKey: &key.PublicKey},
+	}
+	validator := NewClientAssertionValidator(keys, NewReplayCache(), tokenEndpoint)
+
+	signed := func(build func(c Claims)) string {
+		claims := clientAssertionClaims(assertionClientID, tokenEndpoint)
+		build(claims)
+		token, err := claims.Generate(secretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name      string
+		assertion string
+		err       error
+	}{
+		{"unknown client", signed(func(c Claims) { c.SetIssuer("other"); c.SetSubject("other") }), ErrClientUnknown},
+		{"subject", signed(func(c Claims) { c.SetSubject("other") }), ErrClaimValueInvalid},
+		{"audience", signed(func(c Claims) { c.Set(Audience, "https://other.example.com/token") }), ErrTokenAudienceInvalid},
+		{"expired", signed(func(c Claims) { c.SetExpiresAt(time.Now().Add(-time.Minute)) }), ErrTokenHasExpired},
+		{"long lived", signed(func(c Claims) { c.SetExpiresAt(time.Now().Add(time.Hour)) }), ErrTokenLifetimeTooLong},
+		{"missing jti", signed(func(c Claims) { c.DeleteTokenID() }), ErrNotFound},
+	}
+	for _, test := range tests {
+		if _, err := validator.Authenticate(ClientAssertionType, test.assertion); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+
+	// Invalid assertions do not use up their jti
+	assertion, _ := NewClientSecretAssertion(assertionClientID, tokenEndpoint, secretKey)
+	if _, err := validator.Authenticate("urn:other", assertion); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected ErrTokenInvalid for another assertion type, got %v", err)
+	}
+	if _, err := validator.Authenticate(ClientAssertionType, assertion); err != nil {
+		t.Errorf("Authenticate was not successful when it should be: %v", err)
+	}
+
+	// Another secret or key
+	other, _ := NewClientSecretAssertion(assertionClientID, tokenEndpoint, []byte("ThisIsAnotherSecretKeyOfAtLeast32Bytes"))
+	if _, err := validator.Authenticate(ClientAssertionType, other); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+	other, _ = NewPrivateKeyAssertion("key-client", tokenEndpoint, newP256Key(t))
+	if _, err := validator.Authenticate(ClientAssertionType, other); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+
+	// The registered key decides the algorithm
+	claimsEnc, _ := json.Marshal(clientAssertionClaims("key-client", tokenEndpoint))
+	confused, _ := sign(claimsEnc, secretKey)
+	if _, err := validator.Authenticate(ClientAssertionType, confused); !errors.Is(err, ErrTokenAlgorithmMismatch) {
+		t.Errorf("expected ErrTokenAlgorithmMismatch, got %v", err)
+	}
+
+	// Validators need an audience
+	if _, err := NewClientAssertionValidator(keys, nil).Authenticate(ClientAssertionType, assertion); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid without audiences, got %v", err)
+	}
+}
+
+func TestClientAssertionLeeway(t *testing.T) {
+	validator := NewClientAssertionValidator(ClientKeys{assertionClientID: {Secret: secretKey}}, NewReplayCache(), tokenEndpoint)
+	validator.Options = []ValidateOption{WithLeeway(time.Minute)}
+
+	// Expired but still accepted within the leeway
+	claims := clientAssertionClaims(assertionClientID, tokenEndpoint)
+	claims.SetExpiresAt(time.Now().Add(-30 * time.Second))
+	assertion, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := validator.Authenticate(ClientAssertionType, assertion); err != nil {
+		t.Fatalf("expected assertion within the leeway to authenticate, got %v", err)
+	}
+	if _, err := validator.Authenticate(ClientAssertionType, assertion); !errors.Is(err, ErrTokenReplayed) {
+		t.Errorf("expected ErrTokenReplayed within the leeway, got %v", err)
+	}
+}
+
+func TestClientAssertionOptionsCannotWiden(t *testing.T) {
+	validator := NewClientAssertionValidator(ClientKeys{assertionClientID: {Secret: secretKey}}, NewReplayCache(), tokenEndpoint)
+	validator.Options = []ValidateOption{WithAudience("https://other.example.com/token"), WithMaxLifetime(time.Hour)}
+
+	other, _ := NewClientSecretAssertion(assertionClientID, "https://other.example.com/token", secretKey)
+	if _, err := validator.Authenticate(ClientAssertionType, other); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+
+	claims := clientAssertionClaims(assertionClientID, tokenEndpoint)
+	claims.SetExpiresAt(time.Now().Add(30 * time.Minute))
+	longLived, _ := claims.Generate(secretKey)
+	if _, err := validator.Authenticate(ClientAssertionType, longLived); !errors.Is(err, ErrTokenLifetimeTooLong) {
+		t.Errorf("expected ErrTokenLifetimeTooLong, got %v", err)
+	}
+
+	// A zero max lifetime is the default
+	validator = &ClientAssertionValidator{Keys: ClientKeys{assertionClientID: {Secret: secretKey}}, Audiences: []string{tokenEndpoint}}
+	if _, err := validator.Authenticate(ClientAssertionType, longLived); !errors.Is(err, ErrTokenLifetimeTooLong) {
+		t.Errorf("expected ErrTokenLifetimeTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package sjwt
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"maps"
+	"time"
+)
+
+const (
+	// Confirmation holds the key the token is bound to, RFC 7800
+	Confirmation = "cnf"
+
+	// JWKThumbprint is the Confirmation member with the RFC 7638 thumbprint of a DPoP key
+	JWKThumbprint = "jkt"
+
+	// CertificateThumbprint is the Confirmation member with the SHA-256 thumbprint of a mutual TLS client certificate
+	CertificateThumbprint = "x5t#S256"
+)
+
+// SetJWKThumbprint binds the token to the DPoP key with the thumbprint, see JWK.Thumbprint
+func (c Claims) SetJWKThumbprint(thumbprint string) { c.setConfirmation(JWKThumbprint, thumbprint) }
+
+// GetJWKThumbprint will get the DPoP key thumbprint the token is bound to
+func (c Claims) GetJWKThumbprint() (string, error) { return c.getConfirmation(JWKThumbprint) }
+
+// CertificateThumbprintOf returns the RFC 8705 x5t#S256 thumbprint of the certificate,
+// the base64url encoded SHA-256 hash of its DER encoding
+func CertificateThumbprintOf(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SetCertificateThumbprint binds the token to the mutual TLS client certificate
+func (c Claims) SetCertificateThumbprint(cert *x509.Certificate) {
+	c.setConfirmation(CertificateThumbprint, CertificateThumbprintOf(cert))
+}
+
+// GetCertificateThumbprint will get the client certificate thumbprint the token is bound to
+func (c Claims) GetCertificateThumbprint() (string, error) {
+	return c.getConfirmation(CertificateThumbprint)
+}
+
+// validateCertificate checks the token is bound to the client certificate
+func (c Claims) validateCertificate(o *validateOptions, now time.Time) []error {
+	thumbprint, err := c.GetCertificateThumbprint()
+	if err != nil {
+		return []error{&ValidationError{Claim: Confirmation, Err: err, Reason: "x5t#S256 is required for certificate bound tokens", Time: now}}
+	}
+	if o.certificate == nil {
+		return []error{&ValidationError{Claim: Confirmation, Err: ErrTokenProofInvalid, Reason: "requires a client certificate", Time: now}}
+	}
+
+	expected := CertificateThumbprintOf(o.certificate)
+	if subtle.ConstantTimeCompare([]byte(thumbprint), []byte(expected)) != 1 {
+		return []error{&ValidationError{
+			Claim:    Confirmation,
+			Err:      ErrTokenProofInvalid,
+			Reason:   "is not bound to the client certificate",
+			Expected: expected,
+			Actual:   thumbprint,
+			Time:     now,
+		}}
+	}
+
+	return nil
+}
+
+// setConfirmation sets the confirmation member, keeping the other members
+func (c Claims) setConfirmation(name string, value string) {
+	cnf, _ := c[Confirmation].(map[string]any)
+	cnf = maps.Clone(cnf)
+	if cnf == nil {
+		cnf = map[string]any{}
+	}
+	cnf[name] = value
+	c[Confirmation] = cnf
+}
+
+// getConfirmation will get the string confirmation member
+func (c Claims) getConfirmation(name string) (string, error) {
+	val, err := c.GetPath("/" + Confirmation + "/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return "", ErrClaimValueInvalid
+	}
+
+	return s, nil
+}
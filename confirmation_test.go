@@ -0,0 +1,94 @@
+package sjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func TestCertificateThumbprint(t *testing.T) {
+	cert := newCertificate(t)
+	sum := sha256.Sum256(cert.Raw)
+	if thumbprint := CertificateThumbprintOf(cert); thumbprint != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Errorf("unexpected thumbprint %s", thumbprint)
+	}
+
+	claims := New()
+	claims.SetJWKThumbprint("jkt-value")
+	claims.SetCertificateThumbprint(cert)
+
+	token, _ := claims.Generate(secretKey)
+	parsed, _ := Parse(token)
+
+	// Both confirmation members are kept
+	if jkt, _ := parsed.GetJWKThumbprint(); jkt != "jkt-value" {
+		t.Errorf("expected jkt to be kept, got %s", jkt)
+	}
+	if x5t, err := parsed.GetCertificateThumbprint(); err != nil || x5t != CertificateThumbprintOf(cert) {
+		t.Errorf("unexpected x5t#S256 %s %v", x5t, err)
+	}
+	if _, err := New().GetCertificateThumbprint(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	cert := newCertificate(t)
+	claims := New()
+	claims.SetCertificateThumbprint(cert)
+
+	if err := claims.Validate(WithCertificate(cert)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims Claims
+		cert   *x509.Certificate
+		err    error
+	}{
+		{"other certificate", *claims, newCertificate(t), ErrTokenProofInvalid},
+		{"no certificate", *claims, nil, ErrTokenProofInvalid},
+		{"unbound token", Claims{}, cert, ErrNotFound},
+	}
+	for _, test := range tests {
+		err := test.claims.Validate(WithCertificate(test.cert))
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Claim != Confirmation || !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+}
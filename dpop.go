@@ -0,0 +1,270 @@
+package sjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// HTTPMethod is the method of the request a DPoP proof was created for
+	HTTPMethod = "htm"
+
+	// HTTPURI is the url of the request a DPoP proof was created for, without query and fragment
+	HTTPURI = "htu"
+
+	// AccessTokenProofHash is the hash of the access token a DPoP proof is presented with
+	AccessTokenProofHash = "ath"
+
+	dpopType          = "dpop+jwt"
+	defaultDPoPWindow = time.Minute
+)
+
+type dpopHeader struct {
+	Typ string `json:"typ"`
+	Alg string `json:"alg"`
+	JWK *JWK   `json:"jwk"`
+}
+
+// NewDPoPProof creates a RFC 9449 DPoP proof for the request signed with the client key.
+// Pass the access token when calling a resource server so the proof is bound to it
+func NewDPoPProof(key *ecdsa.PrivateKey, method string, uri string, accessToken string) (string, error) {
+	if key == nil {
+		return "", ErrKeyInvalid
+	}
+	jwk, err := NewJWK(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	htu, err := normalizeHTU(uri)
+	if err != nil {
+		return "", err
+	}
+
+	claims := New()
+	claims.SetTokenID()
+	claims.Set(HTTPMethod, method)
+	claims.Set(HTTPURI, htu)
+	claims.SetIssuedAt(time.Now().Truncate(time.Second))
+	if accessToken != "" {
+		claims.Set(AccessTokenProofHash, accessTokenProofHash(accessToken))
+	}
+
+	claimsEnc, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return signES256(dpopHeader{Typ: dpopType, Alg: es256Algorithm, JWK: &jwk}, claimsEnc, key)
+}
+
+// DPoPParams are the request values a DPoP proof is checked against
+type DPoPParams struct {
+	// Method is the http method of the request
+	Method string
+
+	// URL is the absolute url of the request, its query and fragment are ignored
+	URL string
+
+	// AccessToken presented with the proof, ath must match it when set
+	AccessToken string
+
+	// AccessTokenClaims are the verified access token claims, cnf jkt must match the proof key when set
+	AccessTokenClaims Claims
+}
+
+// DPoPVerifier verifies RFC 9449 DPoP proofs on authorization and resource servers
+type DPoPVerifier struct {
+	// Window is how far iat may be from the current time, 1 minute when zero
+	Window time.Duration
+
+	// Replay accepts each proof jti only once when set.
+	// Use a cache dedicated to DPoP proofs
+	Replay *ReplayCache
+}
+
+// NewDPoPVerifier will initiate a new DPoP verifier with the default window
+func NewDPoPVerifier(replay *ReplayCache) *DPoPVerifier {
+	return &DPoPVerifier{
+		Window: defaultDPoPWindow,
+		Replay: replay,
+	}
+}
+
+// Verify checks the proof signature with its jwk header, the htm, htu, iat and ath claims
+// and the binding to the access token. It returns the RFC 7638 thumbprint of the proof key,
+// which authorization servers bind new access tokens to with SetJWKThumbprint
+func (v *DPoPVerifier) Verify(proof string, params DPoPParams) (string, error) {
+	token := splitToken(proof)
+	if len(token) != tokenSegments {
+		return "", ErrTokenInvalid
+	}
+	jwk, err := parseDPoPHeader(token[headerSegmentIdx])
+	if err != nil {
+		return "", err
+	}
+	key, err := jwk.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	if err := verifyES256(token, key); err != nil {
+		return "", err
+	}
+
+	claimsByte, err := decodePayload(token[payloadSegmentIdx])
+	if err != nil {
+		return "", err
+	}
+	claims, err := decodeClaims(claimsByte)
+	if err != nil {
+		return "", err
+	}
+
+	window := v.Window
+	if window == 0 {
+		window = defaultDPoPWindow
+	}
+	thumbprint := jwk.Thumbprint()
+	if err := claims.validateDPoP(window, thumbprint, params); err != nil {
+		return "", err
+	}
+
+	if v.Replay != nil {
+		tokenID, _ := claims.GetTokenID()
+		iat, _ := claims.GetIssuedAtTime()
+		if err := v.Replay.Use(tokenID, iat.Add(window)); err != nil {
+			return "", &ValidationError{Claim: TokenID, Err: err, Reason: "has already been used", Actual: tokenID, Time: time.Now()}
+		}
+	}
+
+	return thumbprint, nil
+}
+
+// validateDPoP checks the proof claims against the request
+func (c Claims) validateDPoP(window time.Duration, thumbprint string, params DPoPParams) error {
+	now := time.Now()
+	invalid := func(name string, reason string, expected any, actual any) error {
+		return &ValidationError{Claim: name, Err: ErrTokenProofInvalid, Reason: reason, Expected: expected, Actual: actual, Time: now}
+	}
+
+	var errs []error
+	if err := c.Validate(WithRequired(TokenID, HTTPMethod, HTTPURI, IssuedAt)); err != nil {
+		errs = append(errs, err)
+	}
+
+	if htm, err := c.GetStr(HTTPMethod); err == nil && htm != params.Method {
+		errs = append(errs, invalid(HTTPMethod, fmt.Sprintf("%q does not match the request method", htm), params.Method, htm))
+	}
+
+	if htu, err := c.GetStr(HTTPURI); err == nil {
+		proofURI, proofErr := normalizeHTU(htu)
+		requestURI, requestErr := normalizeHTU(params.URL)
+		if proofErr != nil || requestErr != nil || proofURI != requestURI {
+			errs = append(errs, invalid(HTTPURI, fmt.Sprintf("%q does not match the request url", htu), requestURI, htu))
+		}
+	}
+
+	if iat, err := c.GetIssuedAtTime(); err == nil {
+		switch {
+		case iat.After(now.Add(window)):
+			errs = append(errs, &ValidationError{
+				Claim:    IssuedAt,
+				Err:      ErrTokenIssuedInFuture,
+				Reason:   fmt.Sprintf("issued %v in the future", roundDuration(iat.Sub(now))),
+				Expected: now,
+				Actual:   iat,
+				Time:     now,
+			})
+		case now.Sub(iat) > window:
+			errs = append(errs, &ValidationError{
+				Claim:    IssuedAt,
+				Err:      ErrTokenTooOld,
+				Reason:   fmt.Sprintf("issued %v ago, window is %v", roundDuration(now.Sub(iat)), window),
+				Expected: window,
+				Actual:   now.Sub(iat),
+				Time:     now,
+			})
+		}
+	}
+
+	if params.AccessToken != "" {
+		ath, _ := c.GetStr(AccessTokenProofHash)
+		if subtle.ConstantTimeCompare([]byte(ath), []byte(accessTokenProofHash(params.AccessToken))) != 1 {
+			errs = append(errs, invalid(AccessTokenProofHash, "does not match the access token", nil, ath))
+		}
+	}
+
+	if params.AccessTokenClaims != nil {
+		jkt, err := params.AccessTokenClaims.GetJWKThumbprint()
+		if err != nil || subtle.ConstantTimeCompare([]byte(jkt), []byte(thumbprint)) != 1 {
+			errs = append(errs, invalid(Confirmation, "access token is not bound to the proof key", jkt, thumbprint))
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// parseDPoPHeader checks the typ and alg headers and returns the public jwk
+func parseDPoPHeader(segment string) (JWK, error) {
+	headerBytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return JWK{}, ErrTokenHeaderInvalid
+	}
+
+	var header dpopHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return JWK{}, ErrTokenHeaderInvalid
+	}
+	if !typeMatches(header.Typ, dpopType) || header.JWK == nil {
+		return JWK{}, ErrTokenHeaderInvalid
+	}
+	if header.Alg != es256Algorithm {
+		return JWK{}, ErrTokenAlgorithmMismatch
+	}
+
+	// The jwk must be a public key
+	var private struct {
+		JWK struct {
+			D *string `json:"d"`
+		} `json:"jwk"`
+	}
+	if err := json.Unmarshal(headerBytes, &private); err != nil || private.JWK.D != nil {
+		return JWK{}, ErrTokenHeaderInvalid
+	}
+
+	return *header.JWK, nil
+}
+
+// normalizeHTU returns the url without query and fragment with a lower case scheme and host
+// and without the default port so equivalent urls compare equal
+func normalizeHTU(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return "", ErrClaimValueInvalid
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if port := u.Port(); (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return scheme + "://" + host + path, nil
+}
+
+// accessTokenProofHash returns the ath value of the access token,
+// the base64url encoded SHA-256 hash of the token
+func accessTokenProofHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
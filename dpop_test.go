@@ -0,0 +1,151 @@
+package sjwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const dpopURL = "https://api.example.com/orders"
+
+func boundClaims(t *testing.T, jwk JWK) Claims {
+	t.Helper()
+
+	claims := New()
+	claims.SetSubject("user-1")
+	claims.SetJWKThumbprint(jwk.Thumbprint())
+
+	token, _ := claims.Generate(secretKey)
+	parsed, err := Parse(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+func TestDPoPProof(t *testing.T) {
+	key := newP256Key(t)
+	jwk, _ := NewJWK(&key.PublicKey)
+
+	proof, err := NewDPoPProof(key, "GET", "HTTPS://API.example.com:443/orders?page=2#top", "access")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headerBytes, _ := base64.RawURLEncoding.DecodeString(strings.Split(proof, ".")[0])
+	var header dpopHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil || header.Typ != "dpop+jwt" || header.Alg != "ES256" || header.JWK == nil || *header.JWK != jwk {
+		t.Errorf("unexpected header %s", headerBytes)
+	}
+
+	claims, _ := decodeClaims(mustDecode(t, strings.Split(proof, ".")[1]))
+	if htu, _ := claims.GetStr(HTTPURI); htu != dpopURL {
+		t.Errorf("expected normalized htu, got %s", htu)
+	}
+
+	verifier := NewDPoPVerifier(NewReplayCache())
+	thumbprint, err := verifier.Verify(proof, DPoPParams{
+		Method:            "GET",
+		URL:               dpopURL + "?page=3",
+		AccessToken:       "access",
+		AccessTokenClaims: boundClaims(t, jwk),
+	})
+	if err != nil {
+		t.Fatalf("Verify was not successful when it should be: %v", err)
+	}
+	if thumbprint != jwk.Thumbprint() {
+		t.Errorf("expected thumbprint %s, got %s", jwk.Thumbprint(), thumbprint)
+	}
+
+	// Each proof is only accepted once
+	_, err = verifier.Verify(proof, DPoPParams{Method: "GET", URL: dpopURL, AccessToken: "access"})
+	if !errors.Is(err, ErrTokenReplayed) {
+		t.Errorf("expected ErrTokenReplayed, got %v", err)
+	}
+}
+
+func TestDPoPVerifierDefaultWindow(t *testing.T) {
+	key := newP256Key(t)
+	proof, err := NewDPoPProof(key, "GET", dpopURL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&DPoPVerifier{}).Verify(proof, DPoPParams{Method: "GET", URL: dpopURL}); err != nil {
+		t.Errorf("expected the default window, got %v", err)
+	}
+}
+
+func TestDPoPVerifierFailures(t *testing.T) {
+	key := newP256Key(t)
+	jwk, _ := NewJWK(&key.PublicKey)
+	other, _ := NewJWK(&newP256Key(t).PublicKey)
+	verifier := NewDPoPVerifier(nil)
+
+	proof, _ := NewDPoPProof(key, "GET", dpopURL, "access")
+	tests := []struct {
+		name   string
+		params DPoPParams
+		claim  string
+	}{
+		{"method", DPoPParams{Method: "POST", URL: dpopURL}, HTTPMethod},
+		{"url", DPoPParams{Method: "GET", URL: "https://api.example.com/users"}, HTTPURI},
+		{"access token", DPoPParams{Method: "GET", URL: dpopURL, AccessToken: "other"}, AccessTokenProofHash},
+		{"binding", DPoPParams{Method: "GET", URL: dpopURL, AccessTokenClaims: boundClaims(t, other)}, Confirmation},
+		{"unbound", DPoPParams{Method: "GET", URL: dpopURL, AccessTokenClaims: Claims{Subject: "user-1"}}, Confirmation},
+	}
+	for _, test := range tests {
+		_, err := verifier.Verify(proof, test.params)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Claim != test.claim || !errors.Is(err, ErrTokenProofInvalid) {
+			t.Errorf("%s: expected ErrTokenProofInvalid on %s, got %v", test.name, test.claim, err)
+		}
+	}
+
+	if _, err := verifier.Verify(proof, DPoPParams{Method: "GET", URL: dpopURL, AccessTokenClaims: boundClaims(t, jwk)}); err != nil {
+		t.Errorf("Verify was not successful when it should be: %v", err)
+	}
+
+	// Stale and future proofs
+	for _, iat := range []time.Time{time.Now().Add(-2 * time.Minute), time.Now().Add(2 * time.Minute)} {
+		claims := Claims{TokenID: ID(), HTTPMethod: "GET", HTTPURI: dpopURL, IssuedAt: NewNumericDate(iat)}
+		claimsEnc, _ := json.Marshal(claims)
+		stale, _ := signES256(dpopHeader{Typ: dpopType, Alg: es256Algorithm, JWK: &jwk}, claimsEnc, key)
+		_, err := verifier.Verify(stale, DPoPParams{Method: "GET", URL: dpopURL})
+		if !errors.Is(err, ErrTokenTooOld) && !errors.Is(err, ErrTokenIssuedInFuture) {
+			t.Errorf("expected proof outside the window to fail, got %v", err)
+		}
+	}
+
+	// The signature must match the jwk header
+	claimsEnc, _ := json.Marshal(Claims{TokenID: ID(), HTTPMethod: "GET", HTTPURI: dpopURL, IssuedAt: NewNumericDate(time.Now())})
+	forged, _ := signES256(dpopHeader{Typ: dpopType, Alg: es256Algorithm, JWK: &other}, claimsEnc, key)
+	if _, err := verifier.Verify(forged, DPoPParams{Method: "GET", URL: dpopURL}); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+
+	// Plain JWTs and private keys in the header are rejected
+	wrongType, _ := signES256(dpopHeader{Typ: jwtType, Alg: es256Algorithm, JWK: &jwk}, claimsEnc, key)
+	if _, err := verifier.Verify(wrongType, DPoPParams{Method: "GET", URL: dpopURL}); !errors.Is(err, ErrTokenHeaderInvalid) {
+		t.Errorf("expected ErrTokenHeaderInvalid, got %v", err)
+	}
+	privateHeader := map[string]any{"typ": dpopType, "alg": es256Algorithm, "jwk": map[string]string{"kty": "EC", "crv": "P-256", "x": jwk.X, "y": jwk.Y, "d": "secret"}}
+	private, _ := signES256(privateHeader, claimsEnc, key)
+	if _, err := verifier.Verify(private, DPoPParams{Method: "GET", URL: dpopURL}); !errors.Is(err, ErrTokenHeaderInvalid) {
+		t.Errorf("expected ErrTokenHeaderInvalid for private jwk, got %v", err)
+	}
+}
+
+func mustDecode(t *testing.T, segment string) []byte {
+	t.Helper()
+
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
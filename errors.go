@@ -1,6 +1,10 @@
 package sjwt
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	// ErrNotFound is an error string clarifying
@@ -11,6 +15,10 @@ var (
 	// that the attempt to retrieve a value could not be properly converted
 	ErrClaimValueInvalid = errors.New("claim value invalid")
 
+	// ErrClaimPathInvalid is an error string clarifying
+	// that the claim path is malformed or cannot be set
+	ErrClaimPathInvalid = errors.New("claim path invalid")
+
 	// ErrTokenInvalid is an error string clarifying
 	// the provided token is an invalid format
 	ErrTokenInvalid = errors.New("token is invalid")
@@ -23,6 +31,15 @@ var (
 	// the current unix timestamp has not exceeded the nbf unix timestamp
 	ErrTokenNotYetValid = errors.New("token is not yet valid")
 
+	// ErrTokenIssuedInFuture clarifies the iat unix timestamp is later than the current unix timestamp
+	ErrTokenIssuedInFuture = errors.New("token issued in the future")
+
+	// ErrTokenTooOld clarifies the iat unix timestamp is older than the max age allowed
+	ErrTokenTooOld = errors.New("token is too old")
+
+	// ErrTokenLifetimeTooLong clarifies the time between iat and exp exceeds the max lifetime allowed
+	ErrTokenLifetimeTooLong = errors.New("token lifetime too long")
+
 	// ErrTokenSignatureInvalid clarifies the token signature did not match the expected value
 	ErrTokenSignatureInvalid = errors.New("token signature invalid")
 
@@ -32,6 +49,98 @@ var (
 	// ErrTokenAlgorithmMismatch clarifies that the token algorithm does not match the supported algorithm
 	ErrTokenAlgorithmMismatch = errors.New("token algorithm mismatch")
 
+	// ErrTokenAudienceInvalid clarifies the token audience does not contain an expected audience
+	ErrTokenAudienceInvalid = errors.New("token audience invalid")
+
+	// ErrTokenIssuerInvalid clarifies the token issuer is not one of the expected issuers
+	ErrTokenIssuerInvalid = errors.New("token issuer invalid")
+
+	// ErrTokenNonceInvalid clarifies the ID token nonce does not match the nonce sent in the authentication request
+	ErrTokenNonceInvalid = errors.New("token nonce invalid")
+
+	// ErrTokenHashInvalid clarifies the ID token at_hash or c_hash does not match the access token or code
+	ErrTokenHashInvalid = errors.New("token hash invalid")
+
+	// ErrTokenProofInvalid clarifies that a DPoP proof or client certificate does not match the request or the token
+	ErrTokenProofInvalid = errors.New("token proof invalid")
+
+	// ErrTokenRevoked clarifies that the token id has been revoked before the token expired
+	ErrTokenRevoked = errors.New("token has been revoked")
+
+	// ErrTokenReplayed clarifies that a one-time token id has already been used
+	ErrTokenReplayed = errors.New("token has already been used")
+
+	// ErrSessionExpired clarifies that a sliding session has exceeded its max age and cannot be renewed
+	ErrSessionExpired = errors.New("session has exceeded its max age")
+
+	// ErrRefreshTokenReused clarifies that a refresh token was used after it had already been rotated
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+
+	// ErrRefreshTokenRevoked clarifies that a refresh token is unknown, expired or its family was revoked
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+
+	// ErrPolicyDenied clarifies that the claims do not satisfy an authorization policy
+	ErrPolicyDenied = errors.New("policy denied")
+
+	// ErrClientUnknown clarifies that a client assertion was issued by a client that is not registered
+	ErrClientUnknown = errors.New("client unknown")
+
+	// ErrKeyInvalid clarifies that a key is not a valid P-256 key
+	ErrKeyInvalid = errors.New("key invalid; use a P-256 ecdsa key")
+
 	// ErrSecretTooShort clarifies that the provided secret is weaker than the minimum required length
 	ErrSecretTooShort = errors.New("secret key too short; use at least 32 random bytes")
 )
+
+// ValidationError describes which claim failed validation and why.
+// It wraps one of the sentinel errors above so errors.Is keeps working
+type ValidationError struct {
+	// Claim is the name of the claim that failed
+	Claim string
+
+	// Err is the sentinel error describing the failure
+	Err error
+
+	// Reason is a human readable explanation such as "expired 42s ago"
+	Reason string
+
+	// Expected and Actual hold the values that were compared, when there are any
+	Expected any
+	Actual   any
+
+	// Time is the time the claims were validated at
+	Time time.Time
+}
+
+// Error returns the sentinel error message followed by the claim and reason
+func (e *ValidationError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%v: claim %q", e.Err, e.Claim)
+	}
+
+	return fmt.Sprintf("%v: claim %q %s", e.Err, e.Claim, e.Reason)
+}
+
+// Unwrap returns the sentinel error
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// joinErrors returns nil, the only error or all errors joined with errors.Join
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// roundDuration rounds d for display in validation reasons
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second && d > -time.Second {
+		return d.Round(time.Millisecond)
+	}
+
+	return d.Round(time.Second)
+}
@@ -0,0 +1,122 @@
+package sjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+const (
+	es256Algorithm = "ES256"
+	es256KeySize   = 32
+)
+
+// JWK is a RFC 7517 P-256 public key, used by DPoP proofs and private_key_jwt client assertions
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewJWK will create a JWK from a P-256 public key
+func NewJWK(key *ecdsa.PublicKey) (JWK, error) {
+	if key == nil || key.Curve != elliptic.P256() {
+		return JWK{}, ErrKeyInvalid
+	}
+	point, err := key.Bytes()
+	if err != nil {
+		return JWK{}, ErrKeyInvalid
+	}
+
+	// Uncompressed points are 0x04 followed by x and y
+	return JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(point[1 : 1+es256KeySize]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[1+es256KeySize:]),
+	}, nil
+}
+
+// PublicKey returns the P-256 public key, checking the point is on the curve
+func (k JWK) PublicKey() (*ecdsa.PublicKey, error) {
+	if k.Kty != "EC" || k.Crv != "P-256" {
+		return nil, ErrKeyInvalid
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil || len(x) != es256KeySize {
+		return nil, ErrKeyInvalid
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil || len(y) != es256KeySize {
+		return nil, ErrKeyInvalid
+	}
+
+	point := append(append([]byte{4}, x...), y...)
+	key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+	if err != nil {
+		return nil, ErrKeyInvalid
+	}
+
+	return key, nil
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of the key, base64url encoded
+func (k JWK) Thumbprint() string {
+	// Required members only, in lexicographic order without whitespace
+	members, _ := json.Marshal(struct {
+		Crv string `json:"crv"`
+		Kty string `json:"kty"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}{k.Crv, k.Kty, k.X, k.Y})
+	sum := sha256.Sum256(members)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// signES256 encodes the header and json payload and signs them with the P-256 private key
+func signES256(header any, claimsEnc []byte, key *ecdsa.PrivateKey) (string, error) {
+	if key == nil || key.Curve != elliptic.P256() {
+		return "", ErrKeyInvalid
+	}
+	headerEnc, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(headerEnc) + "." + base64.RawURLEncoding.EncodeToString(claimsEnc)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	// JWS signatures are the fixed size r and s values concatenated
+	sig := make([]byte, 2*es256KeySize)
+	r.FillBytes(sig[:es256KeySize])
+	s.FillBytes(sig[es256KeySize:])
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// verifyES256 checks the split token signature against the P-256 public key
+func verifyES256(token []string, key *ecdsa.PublicKey) error {
+	sig, err := base64.RawURLEncoding.DecodeString(token[signatureSegmentIdx])
+	if err != nil || len(sig) != 2*es256KeySize {
+		return ErrTokenSignatureInvalid
+	}
+
+	digest := sha256.Sum256([]byte(token[headerSegmentIdx] + "." + token[payloadSegmentIdx]))
+	r := new(big.Int).SetBytes(sig[:es256KeySize])
+	s := new(big.Int).SetBytes(sig[es256KeySize:])
+	if !ecdsa.Verify(key, digest[:], r, s) {
+		return ErrTokenSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package sjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func newP256Key(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestJWK(t *testing.T) {
+	key := newP256Key(t)
+	jwk, err := NewJWK(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := jwk.PublicKey()
+	if err != nil || !pub.Equal(&key.PublicKey) {
+		t.Errorf("public key did not round trip: %v", err)
+	}
+
+	if _, err := NewJWK(nil); !errors.Is(err, ErrKeyInvalid) {
+		t.Errorf("expected ErrKeyInvalid, got %v", err)
+	}
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if _, err := NewJWK(&p384.PublicKey); !errors.Is(err, ErrKeyInvalid) {
+		t.Errorf("expected ErrKeyInvalid for P-384, got %v", err)
+	}
+
+	// Points must be on the curve
+	jwk.Y = jwk.X
+	if _, err := jwk.PublicKey(); !errors.Is(err, ErrKeyInvalid) {
+		t.Errorf("expected ErrKeyInvalid, got %v", err)
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// RFC 9449 example key
+	jwk := JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   "l8tFrhx-34tV3hRICRDY9zCkDlpBhF42UQUfWVAWBFs",
+		Y:   "9VE4jf_Ok_o64zbTTlcuNJajHmt6v9TDVrU0CdvGRDA",
+	}
+	if thumbprint := jwk.Thumbprint(); thumbprint != "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I" {
+		t.Errorf("unexpected thumbprint %s", thumbprint)
+	}
+}
+
+func TestES256(t *testing.T) {
+	key := newP256Key(t)
+	token, err := signES256(map[string]string{"alg": es256Algorithm}, []byte(`{"sub":"user-1"}`), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyES256(splitToken(token), &key.PublicKey); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := verifyES256(splitToken(token), &newP256Key(t).PublicKey); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package sjwt
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	nanosPerSecond = int64(time.Second)
+
+	// NumericDates are limited to the years 1 through 9999 so time.Unix never overflows
+	minNumericDate = -62135596800
+	maxNumericDate = 253402300799
+)
+
+// numericDatePattern is the json number grammar
+var numericDatePattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// NumericDate is a RFC 7519 NumericDate, the seconds since the unix epoch
+// with optional fractional seconds
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate will create a NumericDate keeping sub-second precision
+func NewNumericDate(t time.Time) NumericDate { return NumericDate{t} }
+
+// Seconds returns the seconds since the unix epoch including fractional seconds
+func (d NumericDate) Seconds() float64 {
+	return float64(d.UnixNano()) / float64(nanosPerSecond)
+}
+
+// String returns the NumericDate as a json number
+func (d NumericDate) String() string {
+	sec := d.Unix()
+	nsec := int64(d.Nanosecond())
+	if nsec == 0 {
+		return strconv.FormatInt(sec, 10)
+	}
+
+	// Unix floors towards negative infinity so shift pre-epoch fractions back
+	sign := ""
+	if sec < 0 {
+		sign = "-"
+		sec = -(sec + 1)
+		nsec = nanosPerSecond - nsec
+	}
+
+	frac := strings.TrimRight(strconv.FormatInt(nanosPerSecond+nsec, 10)[1:], "0")
+	return sign + strconv.FormatInt(sec, 10) + "." + frac
+}
+
+// MarshalJSON outputs the NumericDate as a json number
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON reads a json number into the NumericDate
+func (d *NumericDate) UnmarshalJSON(b []byte) error {
+	t, err := parseNumericDate(string(b))
+	if err != nil {
+		return err
+	}
+	d.Time = t
+
+	return nil
+}
+
+// parseNumericDate parses a decimal number of seconds without going through float64
+func parseNumericDate(s string) (time.Time, error) {
+	if !numericDatePattern.MatchString(s) {
+		return time.Time{}, ErrClaimValueInvalid
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, ErrClaimValueInvalid
+		}
+		return toTime(f)
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	sec, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrClaimValueInvalid
+	}
+
+	nsec := int64(0)
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		nsec, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return time.Time{}, ErrClaimValueInvalid
+		}
+	}
+
+	if neg {
+		sec, nsec = -sec, -nsec
+	}
+	if sec < minNumericDate || sec > maxNumericDate {
+		return time.Time{}, ErrClaimValueInvalid
+	}
+
+	return time.Unix(sec, nsec), nil
+}
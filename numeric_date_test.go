@@ -0,0 +1,119 @@
+package sjwt
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNumericDateMarshal(t *testing.T) {
+	tests := []struct {
+		time time.Time
+		want string
+	}{
+		{time.Unix(1700000000, 0), "1700000000"},
+		{time.Unix(1700000000, 500000000), "1700000000.5"},
+		{time.Unix(1700000000, 123456789), "1700000000.123456789"},
+		{time.Unix(-1, 500000000), "-0.5"},
+		{time.Unix(-2, 250000000), "-1.75"},
+	}
+
+	for _, tt := range tests {
+		b, err := json.Marshal(NewNumericDate(tt.time))
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, b)
+		}
+
+		var d NumericDate
+		if err := json.Unmarshal(b, &d); err != nil {
+			t.Fatalf("Unmarshal returned error: %v", err)
+		}
+		if !d.Equal(tt.time) {
+			t.Errorf("expected %v after round trip, got %v", tt.time, d.Time)
+		}
+	}
+}
+
+func TestNumericDateUnmarshal(t *testing.T) {
+	var d NumericDate
+	if err := json.Unmarshal([]byte("1.7e9"), &d); err != nil || d.Unix() != 1700000000 {
+		t.Errorf("exponent form was not read, got: %v %v", d.Time, err)
+	}
+	if err := json.Unmarshal([]byte("1700000000.1234567891"), &d); err != nil || d.Nanosecond() != 123456789 {
+		t.Errorf("extra precision was not truncated, got: %v %v", d.Time, err)
+	}
+	if err := json.Unmarshal([]byte(`"soon"`), &d); err == nil {
+		t.Error("string value should fail to unmarshal")
+	}
+}
+
+func TestNumericDateMalformed(t *testing.T) {
+	for _, s := range []string{"--5", "+5", "1.+5", "1.-5", "1.", ".5", "-", "", "0x10", "1_000", "Inf", "NaN", "01", "1e", "1.5.5"} {
+		if _, err := parseNumericDate(s); err == nil {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+	for _, s := range []string{"0", "-5", "1.5", "1.7e9", "-1.75", "17E+8"} {
+		if _, err := parseNumericDate(s); err != nil {
+			t.Errorf("expected %q to be valid, got %v", s, err)
+		}
+	}
+}
+
+func TestNumericDateOutOfRange(t *testing.T) {
+	for _, s := range []string{"9223372036854775807", "-9223372036854775808", "1e300", "-1e300", "253402300800", "-62135596801"} {
+		if _, err := parseNumericDate(s); !errors.Is(err, ErrClaimValueInvalid) {
+			t.Errorf("expected %q to be out of range, got %v", s, err)
+		}
+	}
+	for _, s := range []string{"253402300799", "-62135596800"} {
+		if _, err := parseNumericDate(s); err != nil {
+			t.Errorf("expected %q to be valid, got %v", s, err)
+		}
+	}
+	for _, val := range []any{int64(math.MaxInt64), uint64(math.MaxUint64), 1e300, -1e300} {
+		if _, err := toTime(val); !errors.Is(err, ErrClaimValueInvalid) {
+			t.Errorf("expected %v to be out of range, got %v", val, err)
+		}
+	}
+
+	// Out of range dates never wrap around into passing checks
+	for _, payload := range []string{`{"exp":9223372036854775807}`, `{"nbf":9223372036854775807}`, `{"nbf":1e300}`} {
+		claims, err := decodeClaims([]byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := claims.Validate(); !errors.Is(err, ErrClaimValueInvalid) {
+			t.Errorf("%s: expected ErrClaimValueInvalid, got %v", payload, err)
+		}
+	}
+}
+
+func TestNumericDateStringClaims(t *testing.T) {
+	claims := Claims{ExpiresAt: "9999999999", NotBeforeAt: "9999999999", IssuedAt: "1700000000"}
+	for _, get := range []func() (time.Time, error){claims.GetExpiresAtTime, claims.GetNotBeforeAtTime, claims.GetIssuedAtTime} {
+		if _, err := get(); !errors.Is(err, ErrClaimValueInvalid) {
+			t.Errorf("expected string time claims to be invalid, got %v", err)
+		}
+	}
+
+	// A string not before must not be skipped
+	err := claims.Validate()
+	for _, name := range []string{ExpiresAt, NotBeforeAt} {
+		found := false
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var validationErr *ValidationError
+			if errors.As(e, &validationErr) && validationErr.Claim == name && errors.Is(e, ErrClaimValueInvalid) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %s to be reported invalid, got %v", name, err)
+		}
+	}
+}
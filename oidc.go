@@ -0,0 +1,213 @@
+package sjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+const (
+	// Nonce binds an ID token to the authentication request that asked for it
+	Nonce = "nonce"
+
+	// AuthTime is a timestamp for when the user authenticated
+	AuthTime = "auth_time"
+
+	// AuthorizedParty is the client the ID token was issued to
+	AuthorizedParty = "azp"
+
+	// AccessTokenHash is the hash of the access token issued alongside the ID token
+	AccessTokenHash = "at_hash"
+
+	// CodeHash is the hash of the authorization code issued alongside the ID token
+	CodeHash = "c_hash"
+)
+
+// IDTokenHash returns the at_hash or c_hash value of an access token or authorization code,
+// the base64url encoded left half of its SHA-256 hash as used by HS256 and ES256 ID tokens
+func IDTokenHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
+
+// IDTokenParams are the values of a single login an ID token is checked against
+type IDTokenParams struct {
+	// Nonce sent in the authentication request, the ID token must contain it when set
+	Nonce string
+
+	// MaxAge sent in the authentication request, auth_time is required and checked when set
+	MaxAge time.Duration
+
+	// AccessToken returned with the ID token, checked against at_hash when the token has one
+	AccessToken string
+
+	// Code returned with the ID token, checked against c_hash when the token has one
+	Code string
+}
+
+// IDTokenValidator validates OpenID Connect ID tokens on the relying party.
+// HS256 ID tokens are verified with the client secret and ES256 ones with the provider P-256 key,
+// other algorithms such as RS256 are not supported
+type IDTokenValidator struct {
+	// Issuer is the provider issuer identifier, iss must equal it exactly
+	Issuer string
+
+	// ClientID is the relying party client id, aud must contain it
+	ClientID string
+
+	// Secret verifies HS256 signatures, the client secret registered with the provider
+	Secret []byte
+
+	// PublicKey verifies ES256 signatures, the provider P-256 key from its jwks_uri
+	PublicKey *ecdsa.PublicKey
+
+	// Options are additional validate options, such as WithLeeway or WithMaxAge to limit iat.
+	// They can only add checks, Issuer and ClientID are always required
+	Options []ValidateOption
+}
+
+// NewIDTokenValidator will initiate a new ID token validator for the provider and client,
+// set PublicKey instead of secret for providers signing with ES256
+func NewIDTokenValidator(issuer string, clientID string, secret []byte) *IDTokenValidator {
+	return &IDTokenValidator{
+		Issuer:   issuer,
+		ClientID: clientID,
+		Secret:   secret,
+	}
+}
+
+// Validate verifies the ID token signature, checks iss, aud, azp, exp, iat and the login params
+// and returns the claims. Failures are returned as *ValidationError joined with errors.Join
+func (v *IDTokenValidator) Validate(idToken string, params IDTokenParams) (Claims, error) {
+	// Without an issuer or client id any provider or client would be accepted
+	if v.Issuer == "" {
+		return nil, ErrTokenIssuerInvalid
+	}
+	if v.ClientID == "" {
+		return nil, ErrTokenAudienceInvalid
+	}
+
+	claimsByte, err := v.verify(idToken)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := decodeClaims(claimsByte)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append([]ValidateOption{WithRequired(Issuer, Subject, Audience, ExpiresAt, IssuedAt)}, v.Options...)
+
+	err = claims.validateWith(opts, func(o *validateOptions, _ time.Time) []error {
+		return claims.validateIDToken(v, params, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// verify checks the header and the signature with the key configured for its algorithm
+func (v *IDTokenValidator) verify(idToken string) ([]byte, error) {
+	token := splitToken(idToken)
+	if len(token) != tokenSegments {
+		return nil, ErrTokenInvalid
+	}
+	header, err := decodeHeader(token[headerSegmentIdx])
+	if err != nil {
+		return nil, err
+	}
+	if !typeMatches(header.Typ, jwtType) {
+		return nil, ErrTokenHeaderInvalid
+	}
+
+	// The configured keys decide the algorithm so a client secret is never used as a public key
+	switch {
+	case header.Alg == jwtAlgorithm && v.Secret != nil:
+		err = verifySignature(token, v.Secret)
+	case header.Alg == es256Algorithm && v.PublicKey != nil:
+		err = verifyES256(token, v.PublicKey)
+	default:
+		err = ErrTokenAlgorithmMismatch
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePayload(token[payloadSegmentIdx])
+}
+
+// validateIDToken checks the issuer and client id exactly, whatever the options allow,
+// and the OpenID Connect specific claims
+func (c Claims) validateIDToken(v *IDTokenValidator, params IDTokenParams, o *validateOptions) []error {
+	now := o.now()
+	clientID := v.ClientID
+	errs := c.validateIssuerAudience(v.Issuer, clientID, now)
+
+	// Tokens for several audiences must say which client they were issued to
+	audience, _ := c.GetAudience()
+	azp, azpErr := c.GetStr(AuthorizedParty)
+	switch {
+	case azpErr == nil && azp != clientID:
+		errs = append(errs, &ValidationError{
+			Claim:    AuthorizedParty,
+			Err:      ErrTokenAudienceInvalid,
+			Reason:   fmt.Sprintf("%q is not the client id", azp),
+			Expected: clientID,
+			Actual:   azp,
+			Time:     now,
+		})
+	case azpErr != nil && len(audience) > 1:
+		errs = append(errs, &ValidationError{Claim: AuthorizedParty, Err: ErrNotFound, Reason: "is required with multiple audiences", Time: now})
+	}
+
+	if params.Nonce != "" {
+		nonce, err := c.GetStr(Nonce)
+		switch {
+		case err != nil:
+			errs = append(errs, &ValidationError{Claim: Nonce, Err: ErrNotFound, Reason: "is required", Time: now})
+		case subtle.ConstantTimeCompare([]byte(nonce), []byte(params.Nonce)) != 1:
+			errs = append(errs, &ValidationError{Claim: Nonce, Err: ErrTokenNonceInvalid, Reason: "does not match the request", Time: now})
+		}
+	}
+
+	if params.MaxAge > 0 {
+		authTime, err := c.getTime(AuthTime)
+		switch {
+		case err != nil:
+			errs = append(errs, &ValidationError{Claim: AuthTime, Err: err, Reason: "is required to check max age", Time: now})
+		case now.Sub(authTime) > params.MaxAge+o.leeway:
+			errs = append(errs, &ValidationError{
+				Claim:    AuthTime,
+				Err:      ErrTokenTooOld,
+				Reason:   fmt.Sprintf("authenticated %v ago, max age is %v", roundDuration(now.Sub(authTime)), params.MaxAge),
+				Expected: params.MaxAge,
+				Actual:   now.Sub(authTime),
+				Time:     now,
+			})
+		}
+	}
+
+	errs = append(errs, c.validateHash(AccessTokenHash, params.AccessToken, now)...)
+	errs = append(errs, c.validateHash(CodeHash, params.Code, now)...)
+
+	return errs
+}
+
+// validateHash checks the hash claim name against value when both are present
+func (c Claims) validateHash(name string, value string, now time.Time) []error {
+	if value == "" || !c.Has(name) {
+		return nil
+	}
+
+	hash, _ := c.GetStr(name)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(IDTokenHash(value))) != 1 {
+		return []error{&ValidationError{Claim: name, Err: ErrTokenHashInvalid, Reason: "does not match", Time: now}}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package sjwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	oidcIssuer   = "https://accounts.example.com"
+	oidcClientID = "client-123"
+)
+
+func idToken(t *testing.T, build func(c *Claims)) string {
+	t.Helper()
+
+	claims := New()
+	claims.SetIssuer(oidcIssuer)
+	claims.SetSubject("user-1")
+	claims.SetAudience([]string{oidcClientID})
+	claims.SetIssuedAt(time.Now())
+	claims.SetExpiresIn(time.Hour)
+	if build != nil {
+		build(claims)
+	}
+
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestIDTokenHash(t *testing.T) {
+	// Left half of the SHA-256 of the access token, base64url encoded
+	if hash := IDTokenHash("jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y"); hash != "77QmUPtjPfzWtF2AnpK9RQ" {
+		t.Errorf("unexpected hash %s", hash)
+	}
+}
+
+func TestIDTokenValidator(t *testing.T) {
+	validator := NewIDTokenValidator(oidcIssuer, oidcClientID, secretKey)
+	params := IDTokenParams{Nonce: "n-0S6_WzA2Mj", MaxAge: 10 * time.Minute, AccessToken: "access", Code: "code"}
+
+	token := idToken(t, func(c *Claims) {
+		c.Set(Nonce, params.Nonce)
+		c.Set(AuthTime, NewNumericDate(time.Now().Add(-time.Minute)))
+		c.Set(AccessTokenHash, IDTokenHash(params.AccessToken))
+		c.Set(CodeHash, IDTokenHash(params.Code))
+	})
+	claims, err := validator.Validate(token, params)
+	if err != nil {
+		t.Fatalf("Validate was not successful when it should be: %v", err)
+	}
+	if subject, _ := claims.GetSubject(); subject != "user-1" {
+		t.Errorf("unexpected subject %s", subject)
+	}
+
+	// Hashes are only checked when the token has them
+	if _, err := validator.Validate(idToken(t, nil), IDTokenParams{AccessToken: "access"}); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	// Another client secret
+	if _, err := NewIDTokenValidator(oidcIssuer, oidcClientID, []byte("ThisIsAnotherSecretKeyOfAtLeast32Bytes")).Validate(token, params); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+}
+
+func TestIDTokenValidatorFailures(t *testing.T) {
+	validator := NewIDTokenValidator(oidcIssuer, oidcClientID, secretKey)
+
+	tests := []struct {
+		name   string
+		build  func(c *Claims)
+		params IDTokenParams
+		claim  string
+		err    error
+	}{
+		{"issuer", func(c *Claims) { c.SetIssuer("https://evil.example") }, IDTokenParams{}, Issuer, ErrTokenIssuerInvalid},
+		{"audience", func(c *Claims) { c.SetAudience([]string{"other"}) }, IDTokenParams{}, Audience, ErrTokenAudienceInvalid},
+		{"missing azp", func(c *Claims) { c.SetAudience([]string{oidcClientID, "other"}) }, IDTokenParams{}, AuthorizedParty, ErrNotFound},
+		{"azp", func(c *Claims) { c.Set(AuthorizedParty, "other") }, IDTokenParams{}, AuthorizedParty, ErrTokenAudienceInvalid},
+		{"expired", func(c *Claims) { c.SetExpiresAt(time.Now().Add(-time.Minute)) }, IDTokenParams{}, ExpiresAt, ErrTokenHasExpired},
+		{"missing iat", func(c *Claims) { c.DeleteIssuedAt() }, IDTokenParams{}, IssuedAt, ErrNotFound},
+		{"missing nonce", nil, IDTokenParams{Nonce: "abc"}, Nonce, ErrNotFound},
+		{"nonce", func(c *Claims) { c.Set(Nonce, "xyz") }, IDTokenParams{Nonce: "abc"}, Nonce, ErrTokenNonceInvalid},
+		{"missing auth_time", nil, IDTokenParams{MaxAge: time.Minute}, AuthTime, ErrNotFound},
+		{"auth_time", func(c *Claims) { c.Set(AuthTime, NewNumericDate(time.Now().Add(-time.Hour))) }, IDTokenParams{MaxAge: time.Minute}, AuthTime, ErrTokenTooOld},
+		{"at_hash", func(c *Claims) { c.Set(AccessTokenHash, IDTokenHash("other")) }, IDTokenParams{AccessToken: "access"}, AccessTokenHash, ErrTokenHashInvalid},
+		{"c_hash", func(c *Claims) { c.Set(CodeHash, IDTokenHash("other")) }, IDTokenParams{Code: "code"}, CodeHash, ErrTokenHashInvalid},
+	}
+	for _, test := range tests {
+		claims, err := validator.Validate(idToken(t, test.build), test.params)
+		if claims != nil {
+			t.Errorf("%s: expected no claims on failure", test.name)
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Claim != test.claim || !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v on %s, got %v", test.name, test.err, test.claim, err)
+		}
+	}
+}
+
+func TestIDTokenValidatorOptionsCannotWiden(t *testing.T) {
+	validator := NewIDTokenValidator(oidcIssuer, oidcClientID, secretKey)
+	validator.Options = []ValidateOption{WithAudience("other"), WithIssuer("https://evil.example")}
+
+	token := idToken(t, func(c *Claims) {
+		c.SetIssuer("https://evil.example")
+		c.SetAudience([]string{"other"})
+	})
+	_, err := validator.Validate(token, IDTokenParams{})
+	if !errors.Is(err, ErrTokenIssuerInvalid) || !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenIssuerInvalid and ErrTokenAudienceInvalid, got %v", err)
+	}
+
+	// Without an issuer or client id nothing is accepted
+	if _, err := NewIDTokenValidator("", oidcClientID, secretKey).Validate(idToken(t, nil), IDTokenParams{}); !errors.Is(err, ErrTokenIssuerInvalid) {
+		t.Errorf("expected ErrTokenIssuerInvalid, got %v", err)
+	}
+	if _, err := NewIDTokenValidator(oidcIssuer, "", secretKey).Validate(idToken(t, nil), IDTokenParams{}); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Errorf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+}
+
+func TestIDTokenValidatorES256(t *testing.T) {
+	key := newP256Key(t)
+	validator := NewIDTokenValidator(oidcIssuer, oidcClientID, nil)
+	validator.PublicKey = &key.PublicKey
+
+	hs256, _ := Parse(idToken(t, nil))
+	claimsEnc, _ := json.Marshal(hs256)
+	token, err := signES256(jwtHeader{Typ: jwtType, Alg: es256Algorithm}, claimsEnc, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validator.Validate(token, IDTokenParams{}); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	// Another provider key
+	other, _ := signES256(jwtHeader{Typ: jwtType, Alg: es256Algorithm}, claimsEnc, newP256Key(t))
+	if _, err := validator.Validate(other, IDTokenParams{}); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+
+	// HS256 tokens need a client secret
+	if _, err := validator.Validate(idToken(t, nil), IDTokenParams{}); !errors.Is(err, ErrTokenAlgorithmMismatch) {
+		t.Errorf("expected ErrTokenAlgorithmMismatch, got %v", err)
+	}
+}
+
+func TestIDTokenValidatorReplayAfterChecks(t *testing.T) {
+	cache := NewReplayCache()
+	token := idToken(t, func(c *Claims) { c.SetTokenID() })
+
+	// A token for another client does not use up its jti
+	other := NewIDTokenValidator(oidcIssuer, "other-client", secretKey)
+	other.Options = []ValidateOption{WithReplayCache(cache)}
+	if _, err := other.Validate(token, IDTokenParams{}); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Fatalf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+	validator := NewIDTokenValidator(oidcIssuer, oidcClientID, secretKey)
+	validator.Options = []ValidateOption{WithReplayCache(cache)}
+	if _, err := validator.Validate(token, IDTokenParams{}); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+	if _, err := validator.Validate(token, IDTokenParams{}); !errors.Is(err, ErrTokenReplayed) {
+		t.Errorf("expected ErrTokenReplayed, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package sjwt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Policy is an authorization rule evaluated against claims.
+// Build policies with Claim and compose them with And, Or and Not
+//
+//	policy := sjwt.And(
+//		sjwt.Claim("tenant").Equals(tenantFromURL),
+//		sjwt.Claim("level").GreaterOrEqual(3),
+//		sjwt.Claim("email_verified").IsTrue(),
+//	)
+//	if err := sjwt.Authorize(policy, claims); err != nil {
+//		log.Println(err) // policy denied: level >= 3 failed, got 2
+//	}
+type Policy interface {
+	// Evaluate returns whether the claims satisfy the policy and, when they do not, why
+	Evaluate(c Claims) (bool, string)
+
+	// String describes the policy, such as `level >= 3`
+	String() string
+}
+
+// PolicyError explains why claims did not satisfy a policy
+type PolicyError struct {
+	Policy string
+	Reason string
+}
+
+// Error returns the reason the policy denied the claims
+func (e *PolicyError) Error() string { return fmt.Sprintf("%v: %s", ErrPolicyDenied, e.Reason) }
+
+// Unwrap returns ErrPolicyDenied
+func (e *PolicyError) Unwrap() error { return ErrPolicyDenied }
+
+// Authorize evaluates the policy and returns a *PolicyError when the claims do not satisfy it
+func Authorize(p Policy, c Claims) error {
+	ok, reason := p.Evaluate(c)
+	if ok {
+		return nil
+	}
+
+	return &PolicyError{Policy: p.String(), Reason: reason}
+}
+
+type policy struct {
+	desc     string
+	evaluate func(c Claims) (bool, string)
+}
+
+func (p policy) Evaluate(c Claims) (bool, string) { return p.evaluate(c) }
+func (p policy) String() string                   { return p.desc }
+
+// And is satisfied when every policy is satisfied
+func And(policies ...Policy) Policy {
+	return policy{
+		desc: joinPolicies(policies, " and "),
+		evaluate: func(c Claims) (bool, string) {
+			var reasons []string
+			for _, p := range policies {
+				if ok, reason := p.Evaluate(c); !ok {
+					reasons = append(reasons, reason)
+				}
+			}
+			if len(reasons) > 0 {
+				return false, strings.Join(reasons, "; ")
+			}
+
+			return true, ""
+		},
+	}
+}
+
+// Or is satisfied when at least one policy is satisfied
+func Or(policies ...Policy) Policy {
+	desc := joinPolicies(policies, " or ")
+	return policy{
+		desc: desc,
+		evaluate: func(c Claims) (bool, string) {
+			reasons := make([]string, 0, len(policies))
+			for _, p := range policies {
+				ok, reason := p.Evaluate(c)
+				if ok {
+					return true, ""
+				}
+				reasons = append(reasons, reason)
+			}
+
+			return false, fmt.Sprintf("none of %s matched: %s", desc, strings.Join(reasons, "; "))
+		},
+	}
+}
+
+// Not is satisfied when the policy is not satisfied
+func Not(p Policy) Policy {
+	desc := fmt.Sprintf("not (%s)", p)
+	return policy{
+		desc: desc,
+		evaluate: func(c Claims) (bool, string) {
+			if ok, _ := p.Evaluate(c); ok {
+				return false, fmt.Sprintf("%s failed, %s is satisfied", desc, p)
+			}
+
+			return true, ""
+		},
+	}
+}
+
+func joinPolicies(policies []Policy, sep string) string {
+	descs := make([]string, len(policies))
+	for i, p := range policies {
+		descs[i] = p.String()
+	}
+
+	return "(" + strings.Join(descs, sep) + ")"
+}
+
+// ClaimRule builds policies for a single claim, name may be a path as accepted by GetPath
+type ClaimRule struct {
+	name string
+}
+
+// Claim starts a policy on the claim name
+func Claim(name string) ClaimRule { return ClaimRule{name: name} }
+
+// leaf builds a policy that fails with the getter error or the actual value
+func (r ClaimRule) leaf(desc string, check func(c Claims) (bool, any, error)) Policy {
+	desc = r.name + " " + desc
+	return policy{
+		desc: desc,
+		evaluate: func(c Claims) (bool, string) {
+			ok, actual, err := check(c)
+			if err != nil {
+				return false, fmt.Sprintf("%s failed, %v", desc, err)
+			}
+			if !ok {
+				return false, fmt.Sprintf("%s failed, got %v", desc, actual)
+			}
+
+			return true, ""
+		},
+	}
+}
+
+// Exists is satisfied when the claim is present
+func (r ClaimRule) Exists() Policy {
+	return r.leaf("exists", func(c Claims) (bool, any, error) {
+		_, err := c.GetPath(r.name)
+		return err == nil, nil, err
+	})
+}
+
+// Equals is satisfied when the claim equals value, compared as strings like GetStr
+func (r ClaimRule) Equals(value any) Policy {
+	want := Claims{r.name: value}
+	expected, _ := want.GetStr(r.name)
+	return r.leaf(fmt.Sprintf("== %q", expected), func(c Claims) (bool, any, error) {
+		actual, err := c.GetPathStr(r.name)
+		return actual == expected, fmt.Sprintf("%q", actual), err
+	})
+}
+
+// In is satisfied when the claim equals one of the values, compared as strings like GetStr
+func (r ClaimRule) In(values ...string) Policy {
+	return r.leaf(fmt.Sprintf("in %q", values), func(c Claims) (bool, any, error) {
+		actual, err := c.GetPathStr(r.name)
+		return slices.Contains(values, actual), fmt.Sprintf("%q", actual), err
+	})
+}
+
+// IsTrue is satisfied when the claim is true, read like GetBool
+func (r ClaimRule) IsTrue() Policy {
+	return r.leaf("is true", func(c Claims) (bool, any, error) {
+		actual, err := c.GetPathBool(r.name)
+		return actual, actual, err
+	})
+}
+
+// Contains is satisfied when the list or space separated claim, such as roles or scope, contains value
+func (r ClaimRule) Contains(value string) Policy {
+	return r.leaf(fmt.Sprintf("contains %q", value), func(c Claims) (bool, any, error) {
+		val, err := c.GetPath(r.name)
+		if err != nil {
+			return false, nil, err
+		}
+		if s, ok := val.(string); ok {
+			list := strings.Fields(s)
+			return slices.Contains(list, value), list, nil
+		}
+		list, err := GetPathAs[[]string](c, r.name)
+
+		return slices.Contains(list, value), list, err
+	})
+}
+
+// GreaterThan is satisfied when the claim is greater than n, compared as a number with its fraction
+func (r ClaimRule) GreaterThan(n float64) Policy {
+	return r.compare(">", n, func(actual float64) bool { return actual > n })
+}
+
+// GreaterOrEqual is satisfied when the claim is greater than or equal to n, compared as a number with its fraction
+func (r ClaimRule) GreaterOrEqual(n float64) Policy {
+	return r.compare(">=", n, func(actual float64) bool { return actual >= n })
+}
+
+// LessThan is satisfied when the claim is less than n, compared as a number with its fraction
+func (r ClaimRule) LessThan(n float64) Policy {
+	return r.compare("<", n, func(actual float64) bool { return actual < n })
+}
+
+// LessOrEqual is satisfied when the claim is less than or equal to n, compared as a number with its fraction
+func (r ClaimRule) LessOrEqual(n float64) Policy {
+	return r.compare("<=", n, func(actual float64) bool { return actual <= n })
+}
+
+// compare reads the claim like GetPathAs so 3.5 is never truncated to 3
+func (r ClaimRule) compare(op string, n float64, ok func(actual float64) bool) Policy {
+	return r.leaf(fmt.Sprintf("%s %v", op, n), func(c Claims) (bool, any, error) {
+		actual, err := GetPathAs[float64](c, r.name)
+		return err == nil && ok(actual), actual, err
+	})
+}
@@ -0,0 +1,107 @@
+package sjwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	claims := New()
+	claims.Set("tenant", "acme")
+	claims.Set("level", 3)
+	claims.Set("score", 3.5)
+	claims.Set("email_verified", true)
+	claims.SetRoles([]string{"admin", "editor"})
+	claims.SetScopes([]string{"orders:read"})
+
+	token, _ := claims.Generate(secretKey)
+	parsed, _ := Parse(token)
+
+	tests := []struct {
+		policy Policy
+		allow  bool
+	}{
+		{Claim("tenant").Equals("acme"), true},
+		{Claim("tenant").Equals("globex"), false},
+		{Claim("tenant").In("globex", "acme"), true},
+		{Claim("level").Equals(3), true},
+		{Claim("level").GreaterOrEqual(3), true},
+		{Claim("level").GreaterThan(3), false},
+		{Claim("level").LessThan(4), true},
+		{Claim("level").LessOrEqual(2), false},
+		{Claim("score").LessOrEqual(3), false},
+		{Claim("score").GreaterThan(3), true},
+		{Claim("score").LessThan(3.7), true},
+		{Claim("score").GreaterOrEqual(3.7), false},
+		{Claim("tenant").GreaterThan(0), false},
+		{Claim("email_verified").IsTrue(), true},
+		{Claim("email_verified").Equals(true), true},
+		{Claim("roles").Contains("admin"), true},
+		{Claim("scope").Contains("orders:read"), true},
+		{Claim("scope").Contains("orders:write"), false},
+		{Claim("missing").Exists(), false},
+		{And(Claim("level").GreaterOrEqual(3), Claim("email_verified").IsTrue()), true},
+		{And(Claim("level").GreaterOrEqual(3), Claim("tenant").Equals("globex")), false},
+		{Or(Claim("tenant").Equals("globex"), Claim("roles").Contains("admin")), true},
+		{Or(Claim("tenant").Equals("globex"), Claim("missing").Exists()), false},
+		{Not(Claim("roles").Contains("banned")), true},
+		{Not(Claim("tenant").Equals("acme")), false},
+	}
+	for _, test := range tests {
+		err := Authorize(test.policy, parsed)
+		if (err == nil) != test.allow {
+			t.Errorf("%s: expected allow %v, got %v", test.policy, test.allow, err)
+		}
+		if err != nil && !errors.Is(err, ErrPolicyDenied) {
+			t.Errorf("%s: expected ErrPolicyDenied, got %v", test.policy, err)
+		}
+	}
+}
+
+func TestPolicyExplanation(t *testing.T) {
+	claims := New()
+	claims.Set("level", 2)
+	claims.Set("email_verified", "false")
+
+	policy := And(Claim("level").GreaterOrEqual(3), Claim("email_verified").IsTrue(), Claim("tenant").Equals("acme"))
+	if policy.String() != `(level >= 3 and email_verified is true and tenant == "acme")` {
+		t.Errorf("unexpected policy description: %s", policy)
+	}
+
+	err := Authorize(policy, *claims)
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected *PolicyError, got %v", err)
+	}
+	if policyErr.Policy != policy.String() {
+		t.Errorf("expected policy %s, got %s", policy, policyErr.Policy)
+	}
+	for _, reason := range []string{"level >= 3 failed, got 2", "email_verified is true failed, got false", `tenant == "acme" failed, ` + ErrNotFound.Error()} {
+		if !strings.Contains(policyErr.Reason, reason) {
+			t.Errorf("expected reason to contain %q, got %q", reason, policyErr.Reason)
+		}
+	}
+
+	err = Authorize(Not(Claim("level").LessThan(3)), *claims)
+	if err == nil || err.Error() != "policy denied: not (level < 3) failed, level < 3 is satisfied" {
+		t.Errorf("unexpected not explanation: %v", err)
+	}
+
+	err = Authorize(Or(Claim("level").Equals(5), Claim("level").Equals(6)), *claims)
+	if err == nil || !strings.Contains(err.Error(), `none of (level == "5" or level == "6") matched`) {
+		t.Errorf("unexpected or explanation: %v", err)
+	}
+}
+
+func TestPolicyPath(t *testing.T) {
+	claims := New()
+	claims.Set("org", map[string]any{"id": "acme", "tier": 2})
+
+	token, _ := claims.Generate(secretKey)
+	parsed, _ := Parse(token)
+
+	if err := Authorize(And(Claim("/org/id").Equals("acme"), Claim("org.tier").GreaterOrEqual(2)), parsed); err != nil {
+		t.Errorf("expected nested claims to satisfy policy, got %v", err)
+	}
+}
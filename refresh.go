@@ -0,0 +1,351 @@
+package sjwt
+
+import (
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	// FamilyID is the refresh token family shared by every rotation of a login session
+	FamilyID = "fid"
+
+	// TokenUse distinguishes access tokens from refresh tokens
+	TokenUse = "token_use"
+
+	// TokenUseAccess is the TokenUse value of access tokens
+	TokenUseAccess = "access"
+
+	// TokenUseRefresh is the TokenUse value of refresh tokens
+	TokenUseRefresh = "refresh"
+
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+
+	// purgeInterval is how often the in-memory stores sweep expired entries
+	purgeInterval = time.Minute
+)
+
+// TokenPair is an access token and the refresh token used to get the next pair
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// RefreshRecord is an issued refresh token kept by a RefreshStore
+type RefreshRecord struct {
+	TokenID   string
+	FamilyID  string
+	ExpiresAt time.Time
+
+	// FamilyExpiresAt is when the family was first issued plus the max lifetime,
+	// no rotation is valid past it
+	FamilyExpiresAt time.Time
+
+	// Claims are the access token claims reissued on every refresh
+	Claims Claims
+}
+
+// RefreshStore keeps track of issued refresh tokens so each can only be used once
+type RefreshStore interface {
+	// Save stores a newly issued refresh token
+	Save(record RefreshRecord) error
+
+	// Use marks the refresh token as used and returns its record.
+	// It must be atomic, returning ErrRefreshTokenReused when the token was already used
+	// and ErrRefreshTokenRevoked when it is unknown or its family was revoked
+	Use(tokenID string) (RefreshRecord, error)
+
+	// Release marks a used refresh token as unused again,
+	// called when the next token pair could not be issued so the client can retry
+	Release(tokenID string) error
+
+	// RevokeFamily revokes every refresh token in the family
+	RevokeFamily(familyID string) error
+}
+
+// Refresher issues access and refresh token pairs and rotates the refresh token on every use.
+// Using a refresh token that was already rotated revokes its whole family
+type Refresher struct {
+	// AccessSecret signs the access tokens
+	AccessSecret []byte
+
+	// RefreshSecret signs the refresh tokens, keep it different from AccessSecret
+	// so a refresh token is never accepted as an access token
+	RefreshSecret []byte
+
+	// Store keeps track of the issued refresh tokens
+	Store RefreshStore
+
+	// AccessTTL is how long access tokens are valid, 15 minutes when zero
+	AccessTTL time.Duration
+
+	// RefreshTTL is how long refresh tokens are valid, 30 days when zero
+	RefreshTTL time.Duration
+
+	// MaxLifetime is how long a family can be rotated after Issue, RefreshTTL when zero.
+	// Rotated refresh tokens never expire after it so a login session has an absolute lifetime
+	MaxLifetime time.Duration
+}
+
+// NewRefresher will initiate a new refresher with the default token lifetimes
+func NewRefresher(accessSecret []byte, refreshSecret []byte, store RefreshStore) *Refresher {
+	return &Refresher{
+		AccessSecret:  accessSecret,
+		RefreshSecret: refreshSecret,
+		Store:         store,
+		AccessTTL:     defaultAccessTTL,
+		RefreshTTL:    defaultRefreshTTL,
+	}
+}
+
+// Issue starts a new refresh token family and issues the first token pair for the claims
+func (r *Refresher) Issue(claims Claims) (TokenPair, error) {
+	base := maps.Clone(claims)
+	for _, name := range []string{TokenID, IssuedAt, NotBeforeAt, ExpiresAt, FamilyID, TokenUse} {
+		base.Del(name)
+	}
+
+	maxLifetime := r.MaxLifetime
+	if maxLifetime == 0 {
+		maxLifetime = r.refreshTTL()
+	}
+
+	return r.issuePair(base, ID(), time.Now().Add(maxLifetime))
+}
+
+// Refresh verifies the refresh token, marks it as used and issues the next token pair in its family.
+// The refresh token is released again when the next pair could not be issued
+func (r *Refresher) Refresh(refreshToken string) (TokenPair, error) {
+	claims, err := r.parseRefreshToken(refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	tokenID, _ := claims.GetTokenID()
+	familyID, _ := claims.GetStr(FamilyID)
+
+	record, err := r.Store.Use(tokenID)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		// A rotated token was presented again so the family may be stolen
+		if revokeErr := r.Store.RevokeFamily(familyID); revokeErr != nil {
+			return TokenPair{}, errors.Join(err, revokeErr)
+		}
+		return TokenPair{}, err
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if record.FamilyID != familyID {
+		return TokenPair{}, ErrTokenInvalid
+	}
+
+	pair, err := r.issuePair(record.Claims, familyID, record.FamilyExpiresAt)
+	if err != nil {
+		if releaseErr := r.Store.Release(tokenID); releaseErr != nil {
+			return TokenPair{}, errors.Join(err, releaseErr)
+		}
+		return TokenPair{}, err
+	}
+
+	return pair, nil
+}
+
+// Revoke verifies the refresh token and revokes its whole family, such as on logout
+func (r *Refresher) Revoke(refreshToken string) error {
+	claims, err := r.parseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+	familyID, _ := claims.GetStr(FamilyID)
+
+	return r.Store.RevokeFamily(familyID)
+}
+
+func (r *Refresher) accessTTL() time.Duration {
+	if r.AccessTTL == 0 {
+		return defaultAccessTTL
+	}
+	return r.AccessTTL
+}
+
+func (r *Refresher) refreshTTL() time.Duration {
+	if r.RefreshTTL == 0 {
+		return defaultRefreshTTL
+	}
+	return r.RefreshTTL
+}
+
+func (r *Refresher) parseRefreshToken(refreshToken string) (Claims, error) {
+	if !Verify(refreshToken, r.RefreshSecret) {
+		return nil, ErrTokenSignatureInvalid
+	}
+
+	claims, err := Parse(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := claims.Validate(WithRequired(TokenID, FamilyID, ExpiresAt)); err != nil {
+		return nil, err
+	}
+	if use, _ := claims.GetStr(TokenUse); use != TokenUseRefresh {
+		return nil, ErrTokenInvalid
+	}
+
+	return claims, nil
+}
+
+func (r *Refresher) issuePair(base Claims, familyID string, familyExpiresAt time.Time) (TokenPair, error) {
+	now := time.Now()
+	pair := TokenPair{
+		AccessExpiresAt:  now.Add(r.accessTTL()),
+		RefreshExpiresAt: now.Add(r.refreshTTL()),
+	}
+	// Records saved without a family expiry are not capped
+	if !familyExpiresAt.IsZero() && familyExpiresAt.Before(pair.RefreshExpiresAt) {
+		pair.RefreshExpiresAt = familyExpiresAt
+	}
+
+	access := maps.Clone(base)
+	access.SetTokenID()
+	access.SetIssuedAt(now)
+	access.SetExpiresAt(pair.AccessExpiresAt)
+	access.Set(TokenUse, TokenUseAccess)
+
+	var err error
+	pair.AccessToken, err = access.Generate(r.AccessSecret)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refresh := New()
+	if subject, err := base.GetSubject(); err == nil {
+		refresh.SetSubject(subject)
+	}
+	refresh.SetTokenID()
+	refresh.Set(FamilyID, familyID)
+	refresh.Set(TokenUse, TokenUseRefresh)
+	refresh.SetIssuedAt(now)
+	refresh.SetExpiresAt(pair.RefreshExpiresAt)
+
+	pair.RefreshToken, err = refresh.Generate(r.RefreshSecret)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	tokenID, _ := refresh.GetTokenID()
+	err = r.Store.Save(RefreshRecord{
+		TokenID:         tokenID,
+		FamilyID:        familyID,
+		ExpiresAt:       pair.RefreshExpiresAt,
+		FamilyExpiresAt: familyExpiresAt,
+		Claims:          base,
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return pair, nil
+}
+
+// MemoryRefreshStore is an in-memory RefreshStore, safe for concurrent use.
+// Records are kept until they expire so reuse of rotated tokens can be detected
+type MemoryRefreshStore struct {
+	mu        sync.Mutex
+	records   map[string]*memoryRefreshRecord
+	families  map[string][]string
+	lastPurge time.Time
+}
+
+type memoryRefreshRecord struct {
+	RefreshRecord
+	used bool
+}
+
+// NewMemoryRefreshStore will initiate a new in-memory refresh store
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		records:  map[string]*memoryRefreshRecord{},
+		families: map[string][]string{},
+	}
+}
+
+// Save stores a newly issued refresh token, expired ones are purged at most once per minute
+func (s *MemoryRefreshStore) Save(record RefreshRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.Sub(s.lastPurge) >= purgeInterval {
+		s.purge(now)
+		s.lastPurge = now
+	}
+	s.records[record.TokenID] = &memoryRefreshRecord{RefreshRecord: record}
+	s.families[record.FamilyID] = append(s.families[record.FamilyID], record.TokenID)
+
+	return nil
+}
+
+// Use marks the refresh token as used and returns its record
+func (s *MemoryRefreshStore) Use(tokenID string) (RefreshRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[tokenID]
+	if !ok || !time.Now().Before(record.ExpiresAt) {
+		return RefreshRecord{}, ErrRefreshTokenRevoked
+	}
+	if record.used {
+		return RefreshRecord{}, ErrRefreshTokenReused
+	}
+	record.used = true
+
+	return record.RefreshRecord, nil
+}
+
+// Release marks a used refresh token as unused again
+func (s *MemoryRefreshStore) Release(tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[tokenID]
+	if !ok {
+		return ErrRefreshTokenRevoked
+	}
+	record.used = false
+
+	return nil
+}
+
+// RevokeFamily revokes every refresh token in the family
+func (s *MemoryRefreshStore) RevokeFamily(familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tokenID := range s.families[familyID] {
+		delete(s.records, tokenID)
+	}
+	delete(s.families, familyID)
+
+	return nil
+}
+
+// purge removes expired records, callers must hold the lock
+func (s *MemoryRefreshStore) purge(now time.Time) {
+	for familyID, tokenIDs := range s.families {
+		live := tokenIDs[:0]
+		for _, tokenID := range tokenIDs {
+			if record, ok := s.records[tokenID]; ok && now.Before(record.ExpiresAt) {
+				live = append(live, tokenID)
+				continue
+			}
+			delete(s.records, tokenID)
+		}
+		if len(live) == 0 {
+			delete(s.families, familyID)
+			continue
+		}
+		s.families[familyID] = live
+	}
+}
@@ -0,0 +1,212 @@
+package sjwt
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var refreshSecretKey = []byte("refresh-0123456789abcdef012345678")
+
+func TestRefresher(t *testing.T) {
+	refresher := NewRefresher(secretKey, refreshSecretKey, NewMemoryRefreshStore())
+
+	claims := New()
+	claims.SetSubject("user:42")
+	claims.Set("role", "admin")
+	pair, err := refresher.Issue(*claims)
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+
+	// Access token carries the claims
+	if !Verify(pair.AccessToken, secretKey) {
+		t.Fatal("access token should verify with the access secret")
+	}
+	access, _ := Parse(pair.AccessToken)
+	role, _ := access.GetStr("role")
+	use, _ := access.GetStr(TokenUse)
+	if role != "admin" || use != TokenUseAccess || access.Validate(WithRequired(TokenID, ExpiresAt)) != nil {
+		t.Errorf("access token claims are incorrect, got: %v", access)
+	}
+
+	// Refresh token cannot be used as an access token
+	if Verify(pair.RefreshToken, secretKey) {
+		t.Error("refresh token should not verify with the access secret")
+	}
+
+	// Rotation issues a new pair in the same family
+	next, err := refresher.Refresh(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if next.RefreshToken == pair.RefreshToken || next.AccessToken == pair.AccessToken {
+		t.Error("refresh should issue new tokens")
+	}
+	first, _ := Parse(pair.RefreshToken)
+	second, _ := Parse(next.RefreshToken)
+	firstFamily, _ := first.GetStr(FamilyID)
+	secondFamily, _ := second.GetStr(FamilyID)
+	firstID, _ := first.GetTokenID()
+	secondID, _ := second.GetTokenID()
+	if firstFamily != secondFamily || firstID == secondID {
+		t.Error("rotated refresh token should share the family with a new token id")
+	}
+	nextAccess, _ := Parse(next.AccessToken)
+	subject, _ := nextAccess.GetSubject()
+	if subject != "user:42" {
+		t.Errorf("refreshed access token lost claims, got: %v", nextAccess)
+	}
+
+	// Reusing a rotated token revokes the family
+	if _, err := refresher.Refresh(pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, err := refresher.Refresh(next.RefreshToken); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("expected ErrRefreshTokenRevoked after reuse, got %v", err)
+	}
+}
+
+func TestRefresherRevoke(t *testing.T) {
+	refresher := NewRefresher(secretKey, refreshSecretKey, NewMemoryRefreshStore())
+	pair, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+
+	if err := refresher.Revoke(pair.RefreshToken); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if _, err := refresher.Refresh(pair.RefreshToken); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("expected ErrRefreshTokenRevoked, got %v", err)
+	}
+}
+
+func TestRefresherInvalidTokens(t *testing.T) {
+	refresher := NewRefresher(secretKey, refreshSecretKey, NewMemoryRefreshStore())
+	pair, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+
+	if _, err := refresher.Refresh(pair.AccessToken); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid for access token, got %v", err)
+	}
+
+	// Signed with the refresh secret but not a refresh token
+	claims := New()
+	claims.SetTokenID()
+	claims.Set(FamilyID, "family")
+	claims.SetExpiresIn(time.Hour)
+	token, _ := claims.Generate(refreshSecretKey)
+	if _, err := refresher.Refresh(token); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected ErrTokenInvalid, got %v", err)
+	}
+
+	// Expired refresh token
+	refresher.RefreshTTL = -time.Minute
+	expired, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+	if _, err := refresher.Refresh(expired.RefreshToken); !errors.Is(err, ErrTokenHasExpired) {
+		t.Errorf("expected ErrTokenHasExpired, got %v", err)
+	}
+}
+
+func TestRefresherConcurrentUse(t *testing.T) {
+	refresher := NewRefresher(secretKey, refreshSecretKey, NewMemoryRefreshStore())
+	pair, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := refresher.Refresh(pair.RefreshToken); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("expected exactly one refresh to succeed, got %d", succeeded)
+	}
+}
+
+// failingRefreshStore fails Save when fail is set
+type failingRefreshStore struct {
+	*MemoryRefreshStore
+	fail bool
+}
+
+func (s *failingRefreshStore) Save(record RefreshRecord) error {
+	if s.fail {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryRefreshStore.Save(record)
+}
+
+func TestRefresherReleaseOnFailure(t *testing.T) {
+	store := &failingRefreshStore{MemoryRefreshStore: NewMemoryRefreshStore()}
+	refresher := NewRefresher(secretKey, refreshSecretKey, store)
+	pair, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+
+	store.fail = true
+	if _, err := refresher.Refresh(pair.RefreshToken); err == nil {
+		t.Fatal("expected Refresh to fail when the store fails")
+	}
+
+	// The refresh token was released so the client can retry
+	store.fail = false
+	if _, err := refresher.Refresh(pair.RefreshToken); err != nil {
+		t.Errorf("expected retry to succeed, got %v", err)
+	}
+}
+
+func TestRefresherMaxLifetime(t *testing.T) {
+	refresher := NewRefresher(secretKey, refreshSecretKey, NewMemoryRefreshStore())
+	refresher.MaxLifetime = time.Hour
+	pair, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+	familyExpiresAt := pair.RefreshExpiresAt
+	if familyExpiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("expected refresh token capped at the max lifetime, got %v", familyExpiresAt)
+	}
+
+	next, err := refresher.Refresh(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if !next.RefreshExpiresAt.Equal(familyExpiresAt) {
+		t.Errorf("expected rotation to keep the family expiry %v, got %v", familyExpiresAt, next.RefreshExpiresAt)
+	}
+}
+
+func TestRefresherDefaults(t *testing.T) {
+	refresher := &Refresher{AccessSecret: secretKey, RefreshSecret: refreshSecretKey, Store: NewMemoryRefreshStore()}
+	pair, err := refresher.Issue(*New())
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+	if time.Until(pair.AccessExpiresAt) < 14*time.Minute || time.Until(pair.RefreshExpiresAt) < 29*24*time.Hour {
+		t.Errorf("expected the default lifetimes, got %v and %v", pair.AccessExpiresAt, pair.RefreshExpiresAt)
+	}
+	if _, err := refresher.Refresh(pair.RefreshToken); err != nil {
+		t.Errorf("Refresh returned error: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package sjwt
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache records seen token ids until their token expires so one-time tokens,
+// such as password reset links and client assertions, are only accepted once.
+// It is safe for concurrent use
+type ReplayCache struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPurge time.Time
+}
+
+// NewReplayCache will initiate a new replay cache
+func NewReplayCache() *ReplayCache {
+	return &ReplayCache{seen: map[string]time.Time{}}
+}
+
+// Use records the token id until the time it expires,
+// returning ErrTokenReplayed when it was already used.
+// Expired token ids are purged at most once per minute
+func (r *ReplayCache) Use(tokenID string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if seenUntil, ok := r.seen[tokenID]; ok && now.Before(seenUntil) {
+		return ErrTokenReplayed
+	}
+
+	// Purge token ids whose tokens can no longer be used anyway
+	if now.Sub(r.lastPurge) >= purgeInterval {
+		for id, seenUntil := range r.seen {
+			if !now.Before(seenUntil) {
+				delete(r.seen, id)
+			}
+		}
+		r.lastPurge = now
+	}
+	r.seen[tokenID] = until
+
+	return nil
+}
+
+// UseClaims records the token id of the claims until they expire, token id and expires at are required.
+// Pass the options the tokens are validated with so a WithLeeway window is covered too
+func (r *ReplayCache) UseClaims(c Claims, opts ...ValidateOption) error {
+	tokenID, err := c.GetTokenID()
+	if err != nil {
+		return err
+	}
+	expiresAt, err := c.GetExpiresAtTime()
+	if err != nil {
+		return err
+	}
+
+	return r.Use(tokenID, expiresAt.Add(newValidateOptions(opts).leeway))
+}
+
+// Len returns the number of token ids that have not been purged yet
+func (r *ReplayCache) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.seen)
+}
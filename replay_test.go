@@ -0,0 +1,121 @@
+package sjwt
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplayCache(t *testing.T) {
+	cache := NewReplayCache()
+
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresIn(time.Hour)
+	if err := claims.Validate(WithReplayCache(cache)); err != nil {
+		t.Fatalf("first use should be accepted, got %v", err)
+	}
+
+	err := claims.Validate(WithReplayCache(cache))
+	var validationErr *ValidationError
+	if !errors.Is(err, ErrTokenReplayed) || !errors.As(err, &validationErr) || validationErr.Claim != TokenID {
+		t.Errorf("expected ErrTokenReplayed, got %v", err)
+	}
+
+	// Token id and expires at are required
+	noExpiry := New()
+	noExpiry.SetTokenID()
+	if err := noExpiry.Validate(WithReplayCache(cache)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestReplayCacheInvalidTokenNotRecorded(t *testing.T) {
+	cache := NewReplayCache()
+
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresIn(time.Hour)
+	claims.SetAudience([]string{"web"})
+	if err := claims.Validate(WithReplayCache(cache), WithAudience("api")); !errors.Is(err, ErrTokenAudienceInvalid) {
+		t.Fatalf("expected ErrTokenAudienceInvalid, got %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Error("invalid token should not be recorded")
+	}
+	if err := claims.Validate(WithReplayCache(cache)); err != nil {
+		t.Errorf("first valid use should be accepted, got %v", err)
+	}
+}
+
+func TestReplayCacheLeeway(t *testing.T) {
+	cache := NewReplayCache()
+
+	// Expired but still accepted within the leeway
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresAt(time.Now().Add(-30 * time.Second))
+	opts := []ValidateOption{WithLeeway(time.Minute), WithReplayCache(cache)}
+
+	if err := claims.Validate(opts...); err != nil {
+		t.Fatalf("first use should be accepted, got %v", err)
+	}
+	if err := claims.Validate(opts...); !errors.Is(err, ErrTokenReplayed) {
+		t.Errorf("expected ErrTokenReplayed within the leeway, got %v", err)
+	}
+}
+
+func TestReplayCachePurge(t *testing.T) {
+	cache := NewReplayCache()
+	if err := cache.Use("expired", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Use returned error: %v", err)
+	}
+	if err := cache.Use("expired", time.Now().Add(time.Hour)); err != nil {
+		t.Errorf("expired token id should be usable again, got %v", err)
+	}
+	if err := cache.Use("stale", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Use returned error: %v", err)
+	}
+	if err := cache.Use("fresh", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Use returned error: %v", err)
+	}
+	if cache.Len() != 3 {
+		t.Errorf("expected no purge within the purge interval, got %d entries", cache.Len())
+	}
+
+	cache.lastPurge = time.Now().Add(-purgeInterval)
+	if err := cache.Use("next", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Use returned error: %v", err)
+	}
+	if cache.Len() != 3 {
+		t.Errorf("expected stale entries to be purged, got %d entries", cache.Len())
+	}
+}
+
+func TestReplayCacheConcurrent(t *testing.T) {
+	cache := NewReplayCache()
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresIn(time.Hour)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := claims.Validate(WithReplayCache(cache)); err == nil {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if accepted != 1 {
+		t.Errorf("expected exactly one use to be accepted, got %d", accepted)
+	}
+}
@@ -0,0 +1,89 @@
+package sjwt
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationStore keeps track of revoked token ids
+type RevocationStore interface {
+	// Revoke revokes the token id until the time the token would expire anyway,
+	// a zero until revokes it forever
+	Revoke(tokenID string, until time.Time) error
+
+	// IsRevoked will let you know whether or not the token id is revoked
+	IsRevoked(tokenID string) (bool, error)
+}
+
+// RevokeClaims revokes the token id of the claims until they expire. Pass the options
+// the tokens are validated with so a WithLeeway window is covered too
+func RevokeClaims(store RevocationStore, c Claims, opts ...ValidateOption) error {
+	tokenID, err := c.GetTokenID()
+	if err != nil {
+		return err
+	}
+	expiresAt, err := c.GetExpiresAtTime()
+	if err != nil {
+		// Without an expiry the token is revoked forever
+		return store.Revoke(tokenID, time.Time{})
+	}
+
+	return store.Revoke(tokenID, expiresAt.Add(newValidateOptions(opts).leeway))
+}
+
+// MemoryRevocationStore is an in-memory RevocationStore, safe for concurrent use.
+// Entries are purged once the token would have expired anyway
+type MemoryRevocationStore struct {
+	mu        sync.Mutex
+	revoked   map[string]time.Time
+	lastPurge time.Time
+}
+
+// NewMemoryRevocationStore will initiate a new in-memory revocation store
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{revoked: map[string]time.Time{}}
+}
+
+// Revoke revokes the token id until the time the token would expire anyway,
+// expired entries are purged at most once per minute
+func (s *MemoryRevocationStore) Revoke(tokenID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.Sub(s.lastPurge) >= purgeInterval {
+		for id, u := range s.revoked {
+			if !u.IsZero() && !now.Before(u) {
+				delete(s.revoked, id)
+			}
+		}
+		s.lastPurge = now
+	}
+	s.revoked[tokenID] = until
+
+	return nil
+}
+
+// IsRevoked will let you know whether or not the token id is revoked
+func (s *MemoryRevocationStore) IsRevoked(tokenID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.revoked[tokenID]
+	if !ok {
+		return false, nil
+	}
+	if !until.IsZero() && !time.Now().Before(until) {
+		delete(s.revoked, tokenID)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Len returns the number of revoked token ids that have not been purged yet
+func (s *MemoryRevocationStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.revoked)
+}
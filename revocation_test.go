@@ -0,0 +1,96 @@
+package sjwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRevocationStore(t *testing.T) {
+	store := NewMemoryRevocationStore()
+
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresIn(time.Hour)
+	if err := claims.Validate(WithRevocationStore(store)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	if err := RevokeClaims(store, *claims); err != nil {
+		t.Fatalf("RevokeClaims returned error: %v", err)
+	}
+	err := claims.Validate(WithRevocationStore(store))
+	var validationErr *ValidationError
+	if !errors.Is(err, ErrTokenRevoked) || !errors.As(err, &validationErr) || validationErr.Claim != TokenID {
+		t.Errorf("expected ErrTokenRevoked, got %v", err)
+	}
+
+	// Other tokens are unaffected
+	other := New()
+	other.SetTokenID()
+	if err := other.Validate(WithRevocationStore(store)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	// Tokens without an id cannot be checked
+	if err := New().Validate(WithRevocationStore(store)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := RevokeClaims(store, *New()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRevocationStoreLeeway(t *testing.T) {
+	store := NewMemoryRevocationStore()
+
+	// Expired but still accepted within the leeway
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresAt(time.Now().Add(-30 * time.Second))
+	opts := []ValidateOption{WithLeeway(time.Minute), WithRevocationStore(store)}
+
+	if err := RevokeClaims(store, *claims, opts...); err != nil {
+		t.Fatalf("RevokeClaims returned error: %v", err)
+	}
+	if err := claims.Validate(opts...); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("expected ErrTokenRevoked within the leeway, got %v", err)
+	}
+}
+
+func TestRevocationStorePurge(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	if err := store.Revoke("expired", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if err := store.Revoke("forever", time.Time{}); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+
+	revoked, _ := store.IsRevoked("expired")
+	if revoked {
+		t.Error("expired entry should no longer be revoked")
+	}
+	revoked, _ = store.IsRevoked("forever")
+	if !revoked {
+		t.Error("entry without expiry should stay revoked")
+	}
+
+	if err := store.Revoke("stale", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if err := store.Revoke("active", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if store.Len() != 3 {
+		t.Errorf("expected no purge within the purge interval, got %d entries", store.Len())
+	}
+
+	store.lastPurge = time.Now().Add(-purgeInterval)
+	if err := store.Revoke("next", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if store.Len() != 3 {
+		t.Errorf("expected stale entries to be purged, got %d entries", store.Len())
+	}
+}
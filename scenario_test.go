@@ -224,7 +224,7 @@ func TestUsageScenarios(t *testing.T) {
 			},
 		},
 		{
-			name: "audience type mismatch detection",
+			name: "single string audience",
 			buildClaims: func() (*Claims, error) {
 				c := New()
 				c.Set(Audience, "single-audience")
@@ -233,6 +233,23 @@ func TestUsageScenarios(t *testing.T) {
 			secret:       []byte("scenario-audience-mismatch-secret-0123"),
 			expectVerify: true,
 			skipValidate: true,
+			assertions: func(t *testing.T, claims Claims) {
+				aud, err := claims.GetAudience()
+				if err != nil || len(aud) != 1 || aud[0] != "single-audience" {
+					t.Fatalf("expected single-audience, got %v %v", aud, err)
+				}
+			},
+		},
+		{
+			name: "audience type mismatch detection",
+			buildClaims: func() (*Claims, error) {
+				c := New()
+				c.Set(Audience, 42)
+				return c, nil
+			},
+			secret:       []byte("scenario-audience-mismatch-secret-0123"),
+			expectVerify: true,
+			skipValidate: true,
 			assertions: func(t *testing.T, claims Claims) {
 				if _, err := claims.GetAudience(); err != ErrClaimValueInvalid {
 					t.Fatalf("expected ErrClaimValueInvalid, got %v", err)
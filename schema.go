@@ -0,0 +1,251 @@
+package sjwt
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ClaimType is the type a claim value must have to satisfy a Schema field
+type ClaimType int
+
+const (
+	// TypeAny accepts any value
+	TypeAny ClaimType = iota
+
+	// TypeString requires a string
+	TypeString
+
+	// TypeInt requires a whole number
+	TypeInt
+
+	// TypeFloat requires a number
+	TypeFloat
+
+	// TypeBool requires a boolean
+	TypeBool
+
+	// TypeStringList requires an array of strings
+	TypeStringList
+
+	// TypeSpaceList requires a space separated string, such as the OAuth scope claim
+	TypeSpaceList
+
+	// TypeObject requires a json object
+	TypeObject
+
+	// TypeArray requires a json array
+	TypeArray
+)
+
+// String returns the name of the claim type
+func (t ClaimType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int"
+	case TypeFloat:
+		return "float"
+	case TypeBool:
+		return "bool"
+	case TypeStringList:
+		return "string list"
+	case TypeSpaceList:
+		return "space separated list"
+	case TypeObject:
+		return "object"
+	case TypeArray:
+		return "array"
+	}
+
+	return "any"
+}
+
+// Range is an inclusive numeric range
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// Field describes the constraints on a single claim.
+// Enum, NotEmpty and Pattern apply to every element of list and array types
+// and to the keys of object types
+type Field struct {
+	Type     ClaimType
+	Required bool
+	NotEmpty bool
+	Enum     []string
+	Pattern  *regexp.Regexp
+	Range    *Range
+}
+
+// Schema maps claim names, or paths as accepted by GetPath, to their constraints
+//
+//	schema := sjwt.Schema{
+//		"tenant_id": {Type: sjwt.TypeString, Required: true, NotEmpty: true},
+//		"scope":     {Type: sjwt.TypeSpaceList, Enum: []string{"read", "write"}},
+//		"level":     {Type: sjwt.TypeInt, Range: &sjwt.Range{Min: 1, Max: 5}},
+//	}
+type Schema map[string]Field
+
+// SchemaViolation describes a single claim that does not satisfy its Schema field
+type SchemaViolation struct {
+	Claim  string
+	Reason string
+	Err    error
+}
+
+// Error returns the claim and the reason it is invalid
+func (v *SchemaViolation) Error() string { return fmt.Sprintf("claim %q %s", v.Claim, v.Reason) }
+
+// Unwrap returns ErrNotFound for missing claims and ErrClaimValueInvalid otherwise
+func (v *SchemaViolation) Unwrap() error { return v.Err }
+
+// SchemaError holds every violation found while validating claims against a Schema
+type SchemaError struct {
+	Violations []*SchemaViolation
+}
+
+// Error returns all violations
+func (e *SchemaError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+
+	return "claims schema invalid: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the violations so errors.Is and errors.As can inspect them
+func (e *SchemaError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, v := range e.Violations {
+		errs[i] = v
+	}
+
+	return errs
+}
+
+// Validate checks the claims against every field and returns a *SchemaError listing all violations
+func (s Schema) Validate(c Claims) error {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var violations []*SchemaViolation
+	for _, name := range names {
+		violations = append(violations, s[name].check(c, name)...)
+	}
+
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+
+	return nil
+}
+
+// check returns the violations of claim name against the field
+func (f Field) check(c Claims, name string) []*SchemaViolation {
+	invalid := func(format string, args ...any) []*SchemaViolation {
+		return []*SchemaViolation{{Claim: name, Reason: fmt.Sprintf(format, args...), Err: ErrClaimValueInvalid}}
+	}
+
+	val, err := c.GetPath(name)
+	if err != nil {
+		if f.Required {
+			return []*SchemaViolation{{Claim: name, Reason: "is required", Err: ErrNotFound}}
+		}
+		return nil
+	}
+
+	values, ok := f.values(c, name, val)
+	if !ok {
+		return invalid("must be a %s", f.Type)
+	}
+
+	if f.NotEmpty && (len(values) == 0 || slices.Contains(values, "")) {
+		return invalid("must not be empty")
+	}
+
+	var violations []*SchemaViolation
+	for _, v := range values {
+		if len(f.Enum) > 0 && !slices.Contains(f.Enum, v) {
+			violations = append(violations, invalid("value %q must be one of %s", v, strings.Join(f.Enum, ", "))...)
+		}
+		if f.Pattern != nil && !f.Pattern.MatchString(v) {
+			violations = append(violations, invalid("value %q must match %s", v, f.Pattern)...)
+		}
+	}
+
+	if f.Range != nil {
+		num, err := GetPathAs[float64](c, name)
+		if err != nil {
+			return append(violations, invalid("must be a number")...)
+		}
+		if num < f.Range.Min || num > f.Range.Max {
+			violations = append(violations, invalid("value %v must be between %v and %v", num, f.Range.Min, f.Range.Max)...)
+		}
+	}
+
+	return violations
+}
+
+// values checks the type of val and returns its string forms for enum and pattern checks
+func (f Field) values(c Claims, name string, val any) ([]string, bool) {
+	switch f.Type {
+	case TypeString:
+		s, ok := val.(string)
+		return []string{s}, ok
+	case TypeInt:
+		if _, err := GetPathAs[int64](c, name); err != nil {
+			return nil, false
+		}
+	case TypeFloat:
+		if _, err := GetPathAs[float64](c, name); err != nil {
+			return nil, false
+		}
+	case TypeBool:
+		if _, ok := val.(bool); !ok {
+			return nil, false
+		}
+	case TypeStringList:
+		list, err := GetPathAs[[]string](c, name)
+		return list, err == nil && val != nil
+	case TypeSpaceList:
+		s, ok := val.(string)
+		return strings.Fields(s), ok
+	case TypeObject:
+		obj, err := GetPathAs[map[string]any](c, name)
+		if err != nil || val == nil {
+			return nil, false
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		return keys, true
+	case TypeArray:
+		v := reflect.ValueOf(val)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, false
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i], _ = Claims{name: v.Index(i).Interface()}.GetStr(name)
+		}
+		return elems, true
+	}
+
+	str, err := c.GetPathStr(name)
+	if err != nil {
+		return nil, false
+	}
+
+	return []string{str}, true
+}
@@ -0,0 +1,122 @@
+package sjwt
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+var testSchema = Schema{
+	"tenant_id": {Type: TypeString, Required: true, NotEmpty: true},
+	"scope":     {Type: TypeSpaceList, Enum: []string{"read", "write", "admin"}},
+	"level":     {Type: TypeInt, Required: true, Range: &Range{Min: 1, Max: 5}},
+	"email":     {Type: TypeString, Pattern: regexp.MustCompile(`^[^@]+@[^@]+$`)},
+	"groups":    {Type: TypeStringList, NotEmpty: true},
+	"verified":  {Type: TypeBool},
+	"org.id":    {Type: TypeString, Required: true},
+}
+
+func TestSchemaValid(t *testing.T) {
+	claims := New()
+	claims.Set("tenant_id", "acme")
+	claims.Set("scope", "read write")
+	claims.Set("level", 3)
+	claims.Set("email", "billy@example.com")
+	claims.Set("groups", []string{"staff"})
+	claims.Set("verified", true)
+	claims.Set("org", map[string]any{"id": "org_1"})
+
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	parsed, err := Parse(token)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if err := testSchema.Validate(parsed); err != nil {
+		t.Errorf("schema should be valid, got: %v", err)
+	}
+	if err := parsed.Validate(WithSchema(testSchema)); err != nil {
+		t.Errorf("Validate with schema should be valid, got: %v", err)
+	}
+}
+
+func TestSchemaViolations(t *testing.T) {
+	claims := New()
+	claims.Set("tenant_id", "")
+	claims.Set("scope", "read delete")
+	claims.Set("level", 9)
+	claims.Set("email", "not-an-email")
+	claims.Set("groups", "staff")
+	claims.Set("verified", "yes")
+
+	err := testSchema.Validate(*claims)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected SchemaError, got %v", err)
+	}
+
+	want := map[string]error{
+		"email":     ErrClaimValueInvalid,
+		"groups":    ErrClaimValueInvalid,
+		"level":     ErrClaimValueInvalid,
+		"org.id":    ErrNotFound,
+		"scope":     ErrClaimValueInvalid,
+		"tenant_id": ErrClaimValueInvalid,
+		"verified":  ErrClaimValueInvalid,
+	}
+	if len(schemaErr.Violations) != len(want) {
+		t.Fatalf("expected %d violations, got %d: %v", len(want), len(schemaErr.Violations), err)
+	}
+	for _, v := range schemaErr.Violations {
+		if !errors.Is(v, want[v.Claim]) {
+			t.Errorf("claim %s expected %v, got %v", v.Claim, want[v.Claim], v.Err)
+		}
+	}
+
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrClaimValueInvalid) {
+		t.Error("schema error should unwrap to its violations")
+	}
+
+	// Validate reports each violation as a *ValidationError
+	err = claims.Validate(WithSchema(testSchema))
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != len(want) {
+		t.Fatalf("expected %d validation errors, got: %v", len(want), err)
+	}
+	for _, e := range joined.Unwrap() {
+		var validationErr *ValidationError
+		if !errors.As(e, &validationErr) || !errors.Is(e, want[validationErr.Claim]) {
+			t.Errorf("expected *ValidationError for a schema violation, got %v", e)
+		}
+	}
+}
+
+func TestSchemaTypes(t *testing.T) {
+	schema := Schema{
+		"float":  {Type: TypeFloat},
+		"object": {Type: TypeObject, Enum: []string{"a", "b"}},
+		"array":  {Type: TypeArray, NotEmpty: true},
+		"int":    {Type: TypeInt},
+	}
+
+	claims := New()
+	claims.Set("float", 1.5)
+	claims.Set("object", map[string]any{"a": 1})
+	claims.Set("array", []any{1, "two"})
+	claims.Set("int", 2.0)
+	if err := schema.Validate(*claims); err != nil {
+		t.Errorf("schema should be valid, got: %v", err)
+	}
+
+	claims.Set("float", "1.5")
+	claims.Set("object", map[string]any{"c": 1})
+	claims.Set("array", []any{})
+	claims.Set("int", 2.5)
+	var schemaErr *SchemaError
+	if err := schema.Validate(*claims); !errors.As(err, &schemaErr) || len(schemaErr.Violations) != 4 {
+		t.Errorf("expected 4 violations, got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package sjwt
+
+import (
+	"maps"
+	"time"
+)
+
+// SessionStartedAt is the time a sliding session started, used to cap its total age
+const SessionStartedAt = "sst"
+
+// SlidingSession extends session tokens while the user stays active.
+// Tokens close to expiring are renewed with a fresh issued at and expires at,
+// up to an absolute max age measured from SessionStartedAt
+type SlidingSession struct {
+	// Secret signs the session tokens
+	Secret []byte
+
+	// TTL is how long each token is valid
+	TTL time.Duration
+
+	// RenewWindow is how close to expiring a token must be before it is renewed, half the TTL when zero
+	RenewWindow time.Duration
+
+	// MaxAge caps the total session length when set, tokens never expire after SessionStartedAt plus MaxAge
+	MaxAge time.Duration
+
+	// RotateTokenID sets a new token id on every renewal
+	RotateTokenID bool
+}
+
+// NewSlidingSession will initiate a new sliding session renewing in the second half of the ttl
+func NewSlidingSession(secret []byte, ttl time.Duration) *SlidingSession {
+	return &SlidingSession{
+		Secret:      secret,
+		TTL:         ttl,
+		RenewWindow: ttl / 2,
+	}
+}
+
+// Start generates the first token from a copy of the claims with the session start,
+// issued at, expires at and a token id when missing set. The claims passed in are not modified
+func (s *SlidingSession) Start(c Claims) (string, error) {
+	now := time.Now()
+	started := maps.Clone(c)
+	if started == nil {
+		started = Claims{}
+	}
+	started.Set(SessionStartedAt, NewNumericDate(now))
+	started.SetIssuedAt(now)
+	started.SetExpiresAt(s.expiresAt(now, now))
+	if !started.Has(TokenID) {
+		started.SetTokenID()
+	}
+
+	return started.Generate(s.Secret)
+}
+
+// Renew takes verified claims and, when they expire within the renewal window,
+// returns a new token with the same claims and a fresh issued at and expires at.
+// It returns false when the token does not need renewing yet or cannot be extended past the max age
+func (s *SlidingSession) Renew(c Claims) (string, bool, error) {
+	now := time.Now()
+	expiresAt, err := c.GetExpiresAtTime()
+	if err != nil {
+		return "", false, err
+	}
+	if !now.Before(expiresAt) {
+		return "", false, ErrTokenHasExpired
+	}
+	renewWindow := s.RenewWindow
+	if renewWindow == 0 {
+		renewWindow = s.TTL / 2
+	}
+	if expiresAt.Sub(now) > renewWindow {
+		return "", false, nil
+	}
+
+	startedAt := now
+	if s.MaxAge > 0 {
+		startedAt, err = c.getTime(SessionStartedAt)
+		if err != nil {
+			return "", false, err
+		}
+		if !now.Before(startedAt.Add(s.MaxAge)) {
+			return "", false, ErrSessionExpired
+		}
+	}
+
+	// Expires at is stored in whole seconds
+	renewedAt := s.expiresAt(now, startedAt)
+	if renewedAt.Unix() <= expiresAt.Unix() {
+		return "", false, nil
+	}
+
+	renewed := maps.Clone(c)
+	renewed.SetIssuedAt(now)
+	renewed.SetExpiresAt(renewedAt)
+	if s.RotateTokenID {
+		renewed.SetTokenID()
+	}
+
+	token, err := renewed.Generate(s.Secret)
+	if err != nil {
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+// expiresAt returns now plus the ttl, capped by the max age from startedAt
+func (s *SlidingSession) expiresAt(now time.Time, startedAt time.Time) time.Time {
+	expiresAt := now.Add(s.TTL)
+	if s.MaxAge > 0 && expiresAt.After(startedAt.Add(s.MaxAge)) {
+		return startedAt.Add(s.MaxAge)
+	}
+
+	return expiresAt
+}
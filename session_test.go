@@ -0,0 +1,120 @@
+package sjwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSlidingSession(t *testing.T) {
+	session := NewSlidingSession(secretKey, time.Hour)
+
+	claims := New()
+	claims.SetSubject("user:42")
+	token, err := session.Start(*claims)
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	parsed, _ := Parse(token)
+	if err := parsed.Validate(WithRequired(SessionStartedAt, IssuedAt, ExpiresAt, TokenID)); err != nil {
+		t.Fatalf("session token is missing claims: %v", err)
+	}
+	if len(*claims) != 1 {
+		t.Errorf("Start should not modify the claims passed in, got: %v", *claims)
+	}
+
+	// Outside the renewal window nothing happens
+	renewed, ok, err := session.Renew(parsed)
+	if err != nil || ok || renewed != "" {
+		t.Errorf("fresh token should not renew, got: %v %v %v", renewed, ok, err)
+	}
+
+	// Inside the renewal window a new token is issued with the same claims
+	parsed.SetIssuedAt(time.Now().Add(-50 * time.Minute))
+	parsed.SetExpiresIn(10 * time.Minute)
+	renewed, ok, err = session.Renew(parsed)
+	if err != nil || !ok {
+		t.Fatalf("token should renew, got: %v %v", ok, err)
+	}
+	renewedClaims, _ := Parse(renewed)
+	until, _ := renewedClaims.TimeUntilExpiry()
+	subject, _ := renewedClaims.GetSubject()
+	oldID, _ := parsed.GetTokenID()
+	newID, _ := renewedClaims.GetTokenID()
+	if until < 59*time.Minute || subject != "user:42" || oldID != newID {
+		t.Errorf("renewed token is incorrect, got: %v", renewedClaims)
+	}
+
+	// Expired tokens are not renewed
+	parsed.SetExpiresIn(-time.Second)
+	if _, _, err := session.Renew(parsed); !errors.Is(err, ErrTokenHasExpired) {
+		t.Errorf("expected ErrTokenHasExpired, got %v", err)
+	}
+}
+
+func TestSlidingSessionDefaultRenewWindow(t *testing.T) {
+	session := &SlidingSession{Secret: secretKey, TTL: time.Hour}
+
+	claims := New()
+	claims.SetExpiresIn(10 * time.Minute)
+	if _, ok, err := session.Renew(*claims); err != nil || !ok {
+		t.Errorf("expected renewal in the second half of the ttl, got: %v %v", ok, err)
+	}
+}
+
+func TestSlidingSessionRotateTokenID(t *testing.T) {
+	session := NewSlidingSession(secretKey, time.Hour)
+	session.RotateTokenID = true
+
+	claims := New()
+	claims.SetTokenID()
+	claims.SetExpiresIn(time.Minute)
+	renewed, ok, err := session.Renew(*claims)
+	if err != nil || !ok {
+		t.Fatalf("token should renew, got: %v %v", ok, err)
+	}
+	renewedClaims, _ := Parse(renewed)
+	oldID, _ := claims.GetTokenID()
+	newID, _ := renewedClaims.GetTokenID()
+	if oldID == newID {
+		t.Error("token id should have been rotated")
+	}
+}
+
+func TestSlidingSessionMaxAge(t *testing.T) {
+	session := NewSlidingSession(secretKey, time.Hour)
+	session.MaxAge = 8 * time.Hour
+	now := time.Now()
+
+	// Renewal is capped by the max age
+	claims := New()
+	claims.Set(SessionStartedAt, NewNumericDate(now.Add(-7*time.Hour-30*time.Minute)))
+	claims.SetExpiresIn(10 * time.Minute)
+	renewed, ok, err := session.Renew(*claims)
+	if err != nil || !ok {
+		t.Fatalf("token should renew, got: %v %v", ok, err)
+	}
+	renewedClaims, _ := Parse(renewed)
+	until, _ := renewedClaims.TimeUntilExpiry()
+	if until > 31*time.Minute || until < 29*time.Minute {
+		t.Errorf("renewal should be capped at the max age, got %v", until)
+	}
+
+	// A token already expiring at the cap is not renewed
+	renewed, ok, err = session.Renew(renewedClaims)
+	if err != nil || ok || renewed != "" {
+		t.Errorf("capped token should not renew, got: %v %v", ok, err)
+	}
+
+	// Past the max age the session is over
+	claims.Set(SessionStartedAt, NewNumericDate(now.Add(-9*time.Hour)))
+	if _, _, err := session.Renew(*claims); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+
+	// The session start is required
+	claims.Del(SessionStartedAt)
+	if _, _, err := session.Renew(*claims); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -1,14 +1,17 @@
 package sjwt
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"strings"
 )
 
 const (
 	jwtType             = "JWT"
+	accessTokenType     = "at+jwt"
 	jwtAlgorithm        = "HS256"
 	minSecretLength     = 32
 	tokenSegments       = 3
@@ -24,16 +27,26 @@ type jwtHeader struct {
 
 // Generate takes in claims and a secret and outputs jwt token
 func (c Claims) Generate(secret []byte) (string, error) {
-	if len(secret) < minSecretLength {
-		return "", ErrSecretTooShort
-	}
-	// Encode header and claims
-	headerEnc, err := json.Marshal(jwtHeader{Typ: jwtType, Alg: jwtAlgorithm})
+	claimsEnc, err := json.Marshal(c)
 	if err != nil {
 		return "", err
 	}
 
-	claimsEnc, err := json.Marshal(c)
+	return sign(claimsEnc, secret)
+}
+
+// sign encodes the header and json payload and signs them with the secret
+func sign(claimsEnc []byte, secret []byte) (string, error) {
+	return signType(jwtType, claimsEnc, secret)
+}
+
+// signType signs the json payload with the typ header set to the explicit type, such as at+jwt
+func signType(typ string, claimsEnc []byte, secret []byte) (string, error) {
+	if len(secret) < minSecretLength {
+		return "", ErrSecretTooShort
+	}
+	// Encode header and claims
+	headerEnc, err := json.Marshal(jwtHeader{Typ: typ, Alg: jwtAlgorithm})
 	if err != nil {
 		return "", err
 	}
@@ -66,7 +79,8 @@ func (c Claims) Generate(secret []byte) (string, error) {
 	return string(token), nil
 }
 
-// Parse takes in the token string and returns the claims payload (without verifying the signature)
+// Parse takes in the token string and returns the claims payload (without verifying the signature).
+// Numbers are decoded as json.Number so large integers are not rounded
 func Parse(tokenStr string) (Claims, error) {
 	tokenArray := splitToken(tokenStr)
 	if len(tokenArray) != tokenSegments {
@@ -77,21 +91,57 @@ func Parse(tokenStr string) (Claims, error) {
 		return nil, err
 	}
 
-	payload := tokenArray[payloadSegmentIdx]
+	claimsByte, err := decodePayload(tokenArray[payloadSegmentIdx])
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeClaims(claimsByte)
+}
+
+// verifyPayload checks the header and signature of the token and returns its decoded payload
+func verifyPayload(tokenStr string, secret []byte) ([]byte, error) {
+	return verifyPayloadType(tokenStr, secret, jwtType)
+}
+
+// verifyPayloadType is verifyPayload for tokens with an explicit typ header, such as at+jwt
+func verifyPayloadType(tokenStr string, secret []byte, typ string) ([]byte, error) {
+	token := splitToken(tokenStr)
+	if len(token) != tokenSegments {
+		return nil, ErrTokenInvalid
+	}
+	if err := validateHeaderType(token[headerSegmentIdx], typ); err != nil {
+		return nil, err
+	}
+	if err := verifySignature(token, secret); err != nil {
+		return nil, err
+	}
 
+	return decodePayload(token[payloadSegmentIdx])
+}
+
+func decodePayload(payload string) ([]byte, error) {
 	decodedLen := base64.RawURLEncoding.DecodedLen(len(payload))
 	claimsByte := make([]byte, decodedLen)
 	n, err := base64.RawURLEncoding.Decode(claimsByte, []byte(payload))
 	if err != nil {
 		return nil, err
 	}
-	claimsByte = claimsByte[:n]
 
+	return claimsByte[:n], nil
+}
+
+func decodeClaims(claimsByte []byte) (Claims, error) {
+	// Decode numbers as json.Number so large integers keep their precision
 	var claims Claims
-	err = json.Unmarshal(claimsByte, &claims)
-	if err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(claimsByte))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
 		return nil, err
 	}
+	if decoder.More() {
+		return nil, ErrTokenInvalid
+	}
 
 	return claims, nil
 }
@@ -136,17 +186,18 @@ func verifySignature(token []string, secret []byte) error {
 }
 
 func validateHeader(segment string) error {
-	headerBytes, err := base64.RawURLEncoding.DecodeString(segment)
-	if err != nil {
-		return ErrTokenHeaderInvalid
-	}
+	return validateHeaderType(segment, jwtType)
+}
 
-	var header jwtHeader
-	if err := json.Unmarshal(headerBytes, &header); err != nil {
-		return ErrTokenHeaderInvalid
+// validateHeaderType checks the algorithm and the typ header. The JWT type may be omitted,
+// explicit types such as at+jwt are required and may use the application/ media type form
+func validateHeaderType(segment string, typ string) error {
+	header, err := decodeHeader(segment)
+	if err != nil {
+		return err
 	}
 
-	if header.Typ != "" && header.Typ != jwtType {
+	if !typeMatches(header.Typ, typ) {
 		return ErrTokenHeaderInvalid
 	}
 	if header.Alg != jwtAlgorithm {
@@ -155,6 +206,29 @@ func validateHeader(segment string) error {
 	return nil
 }
 
+func decodeHeader(segment string) (jwtHeader, error) {
+	headerBytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return jwtHeader{}, ErrTokenHeaderInvalid
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return jwtHeader{}, ErrTokenHeaderInvalid
+	}
+
+	return header, nil
+}
+
+// typeMatches reports whether the typ header is the expected type
+func typeMatches(headerTyp string, typ string) bool {
+	if typ == jwtType {
+		return headerTyp == "" || headerTyp == jwtType
+	}
+
+	return strings.EqualFold(strings.TrimPrefix(strings.ToLower(headerTyp), "application/"), typ)
+}
+
 func splitToken(token string) []string {
 	if token == "" {
 		return []string{}
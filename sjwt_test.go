@@ -51,6 +51,43 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseLargeNumbers(t *testing.T) {
+	claims := New()
+	claims.Set("user_id", int64(1234567890123456789))
+	claims.Set("account", uint64(18446744073709551615))
+	claims.Set("price", 19.99)
+	jwt, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	newClaims, err := Parse(jwt)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	userID, err := newClaims.GetInt64("user_id")
+	if err != nil || userID != 1234567890123456789 {
+		t.Errorf("user_id lost precision, got: %v %v", userID, err)
+	}
+	userIDStr, _ := newClaims.GetStr("user_id")
+	if userIDStr != "1234567890123456789" {
+		t.Errorf("user_id string lost precision, got: %v", userIDStr)
+	}
+	account, err := newClaims.GetUint64("account")
+	if err != nil || account != 18446744073709551615 {
+		t.Errorf("account lost precision, got: %v %v", account, err)
+	}
+	price, err := newClaims.GetFloat("price")
+	if err != nil || price != 19.99 {
+		t.Errorf("price is incorrect, got: %v %v", price, err)
+	}
+	userIDAs, err := GetAs[int64](newClaims, "user_id")
+	if err != nil || userIDAs != 1234567890123456789 {
+		t.Errorf("GetAs user_id lost precision, got: %v %v", userIDAs, err)
+	}
+}
+
 func TestParseEmpty(t *testing.T) {
 	_, err := Parse("")
 	if err != ErrTokenInvalid {
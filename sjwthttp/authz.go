@@ -0,0 +1,66 @@
+package sjwthttp
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/brianvoe/sjwt"
+)
+
+// RequireScopes only lets requests through whose claims were granted every scope.
+// Use it after Middleware, requests without claims are answered with 401
+// and missing scopes with a RFC 6750 insufficient_scope 403
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return require(func(_ *http.Request, claims sjwt.Claims) bool { return claims.HasAllScopes(scopes...) }, strings.Join(scopes, " "))
+}
+
+// RequireRoles only lets requests through whose claims have at least one of the roles.
+// Use it after Middleware, requests without claims are answered with 401
+// and missing roles with a RFC 6750 insufficient_scope 403
+func RequireRoles(roles ...string) func(http.Handler) http.Handler {
+	return require(func(_ *http.Request, claims sjwt.Claims) bool { return slices.ContainsFunc(roles, claims.HasRole) }, "")
+}
+
+func require(allowed func(*http.Request, sjwt.Claims) bool, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := FromContext(r.Context())
+			if !ok {
+				writeChallenge(w, http.StatusUnauthorized, challengeScheme(r), "", "", "", "")
+				return
+			}
+			if !allowed(r, claims) {
+				writeChallenge(w, http.StatusForbidden, challengeScheme(r), "", ErrorInsufficientScope, "", scope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePolicy only lets requests through whose claims satisfy the policy built for the request,
+// such as a policy comparing the tenant claim to the tenant in the URL.
+// Use it after Middleware, requests without claims are answered with 401
+// and denied requests with a RFC 6750 insufficient_scope 403.
+// onDenied is called with the *sjwt.PolicyError of denied requests when set, such as to log why
+func RequirePolicy(policy func(r *http.Request) sjwt.Policy, onDenied func(r *http.Request, err error)) func(http.Handler) http.Handler {
+	return require(func(r *http.Request, claims sjwt.Claims) bool {
+		err := sjwt.Authorize(policy(r), claims)
+		if err != nil && onDenied != nil {
+			onDenied(r, err)
+		}
+		return err == nil
+	}, "")
+}
+
+// challengeScheme answers DPoP requests with a DPoP challenge and every other request with Bearer
+func challengeScheme(r *http.Request) string {
+	scheme, _, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if strings.EqualFold(scheme, schemeDPoP) {
+		return schemeDPoP
+	}
+
+	return schemeBearer
+}
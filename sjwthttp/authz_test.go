@@ -0,0 +1,95 @@
+package sjwthttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/sjwt"
+)
+
+func TestRequireScopes(t *testing.T) {
+	auth := Middleware(Options{Secret: secretKey})
+	handler := auth(RequireScopes("orders:read", "orders:write")(subjectHandler()))
+
+	w := serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetScopes([]string{"orders:read", "orders:write"}) }))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected success, got %d", w.Code)
+	}
+
+	w = serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetScopes([]string{"orders:read"}) }))
+	challenge := w.Header().Get("WWW-Authenticate")
+	if w.Code != http.StatusForbidden || challenge != `Bearer error="insufficient_scope", scope="orders:read orders:write"` {
+		t.Errorf("expected insufficient_scope, got %d %s", w.Code, challenge)
+	}
+
+	// Without Middleware there are no claims
+	w = serve(RequireScopes("orders:read")(subjectHandler()))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without claims, got %d", w.Code)
+	}
+}
+
+func TestRequireRoles(t *testing.T) {
+	auth := Middleware(Options{Secret: secretKey})
+	handler := auth(RequireRoles("admin", "owner")(subjectHandler()))
+
+	w := serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetRoles([]string{"owner"}) }))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected success, got %d", w.Code)
+	}
+
+	w = serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetRoles([]string{"viewer"}) }))
+	if w.Code != http.StatusForbidden || w.Header().Get("WWW-Authenticate") != `Bearer error="insufficient_scope"` {
+		t.Errorf("expected insufficient_scope, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func TestRequireScopesDPoPChallenge(t *testing.T) {
+	handler := RequireScopes("orders:write")(subjectHandler())
+
+	claims := sjwt.Claims{}
+	claims.SetScopes([]string{"orders:read"})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "DPoP token")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(NewContext(req.Context(), claims)))
+	if w.Code != http.StatusForbidden || w.Header().Get("WWW-Authenticate") != `DPoP error="insufficient_scope", scope="orders:write"` {
+		t.Errorf("expected a DPoP insufficient_scope challenge, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func TestRequirePolicy(t *testing.T) {
+	auth := Middleware(Options{Secret: secretKey})
+	var denied error
+	handler := auth(RequirePolicy(func(r *http.Request) sjwt.Policy {
+		return sjwt.And(sjwt.Claim("tenant").Equals(r.URL.Query().Get("tenant")), sjwt.Claim("level").GreaterOrEqual(3))
+	}, func(_ *http.Request, err error) { denied = err })(subjectHandler()))
+
+	token := generate(t, func(c *sjwt.Claims) {
+		c.Set("tenant", "acme")
+		c.Set("level", 3)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?tenant=acme", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected success, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?tenant=globex", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for another tenant, got %d", w.Code)
+	}
+	var policyErr *sjwt.PolicyError
+	if !errors.As(denied, &policyErr) || !strings.Contains(policyErr.Reason, `tenant == "globex" failed`) {
+		t.Errorf("expected the policy error in onDenied, got %v", denied)
+	}
+}
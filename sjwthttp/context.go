@@ -0,0 +1,20 @@
+package sjwthttp
+
+import (
+	"context"
+
+	"github.com/brianvoe/sjwt"
+)
+
+type claimsKey struct{}
+
+// NewContext returns a copy of ctx carrying the claims
+func NewContext(ctx context.Context, claims sjwt.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims stored by Middleware, if any
+func FromContext(ctx context.Context) (sjwt.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(sjwt.Claims)
+	return claims, ok
+}
@@ -0,0 +1,94 @@
+package sjwthttp
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/brianvoe/sjwt"
+)
+
+// DefaultCookieName is the cookie name used when CookieOptions.Name is empty
+const DefaultCookieName = "sjwt"
+
+// CookieOptions configures session cookies.
+// The zero value is a Secure, HttpOnly, SameSite=Lax cookie on path "/"
+type CookieOptions struct {
+	// Name of the cookie, DefaultCookieName when empty
+	Name string
+
+	// Path of the cookie, "/" when empty
+	Path string
+
+	// Domain of the cookie, host only when empty
+	Domain string
+
+	// SameSite mode of the cookie, http.SameSiteLaxMode when zero
+	SameSite http.SameSite
+
+	// Insecure allows the cookie over plain http, only use it for local development
+	Insecure bool
+}
+
+func (o CookieOptions) cookie(value string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     o.Name,
+		Value:    value,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		Secure:   !o.Insecure,
+		HttpOnly: true,
+		SameSite: o.SameSite,
+	}
+	if cookie.Name == "" {
+		cookie.Name = DefaultCookieName
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 || cookie.SameSite == http.SameSiteDefaultMode {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+
+	return cookie
+}
+
+// SetCookie generates a token from the claims and writes it as a cookie.
+// The cookie expires with the token, or with the browser session when the claims have no expires at
+func SetCookie(w http.ResponseWriter, claims sjwt.Claims, secret []byte, opts CookieOptions) error {
+	token, err := claims.Generate(secret)
+	if err != nil {
+		return err
+	}
+
+	cookie := opts.cookie(token)
+	if expiresAt, err := claims.GetExpiresAtTime(); err == nil {
+		maxAge := math.Ceil(time.Until(expiresAt).Seconds())
+		if maxAge <= 0 {
+			return sjwt.ErrTokenHasExpired
+		}
+		cookie.MaxAge = int(maxAge)
+		cookie.Expires = expiresAt.UTC()
+	}
+	http.SetCookie(w, cookie)
+
+	return nil
+}
+
+// ReadCookie extracts the token from the cookie, verifies its signature and validates the claims
+func ReadCookie(r *http.Request, secret []byte, opts CookieOptions, validateOpts ...sjwt.ValidateOption) (sjwt.Claims, error) {
+	token, err := CookieExtractor(opts.cookie("").Name).ExtractToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticate(token, secret, nil, validateOpts...)
+}
+
+// ClearCookie expires the cookie, such as on logout
+func ClearCookie(w http.ResponseWriter, opts CookieOptions) {
+	cookie := opts.cookie("")
+	cookie.MaxAge = -1
+	cookie.Expires = time.Unix(0, 0)
+	http.SetCookie(w, cookie)
+}
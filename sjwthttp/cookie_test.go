@@ -0,0 +1,88 @@
+package sjwthttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/sjwt"
+)
+
+func TestSetCookie(t *testing.T) {
+	claims := sjwt.New()
+	claims.SetSubject("user:42")
+	claims.SetExpiresIn(time.Hour)
+
+	w := httptest.NewRecorder()
+	if err := SetCookie(w, *claims, secretKey, CookieOptions{}); err != nil {
+		t.Fatalf("SetCookie returned error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != DefaultCookieName || cookie.Path != "/" || !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("cookie does not have secure defaults, got: %+v", cookie)
+	}
+	if cookie.MaxAge < 3599 || cookie.MaxAge > 3600 {
+		t.Errorf("cookie max age should follow expires at, got %d", cookie.MaxAge)
+	}
+
+	// Read it back
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+	read, err := ReadCookie(r, secretKey, CookieOptions{})
+	if err != nil {
+		t.Fatalf("ReadCookie returned error: %v", err)
+	}
+	subject, _ := read.GetSubject()
+	if subject != "user:42" {
+		t.Errorf("expected subject user:42, got %s", subject)
+	}
+
+	if _, err := ReadCookie(r, secretKey, CookieOptions{}, sjwt.WithAudience("api")); !errors.Is(err, sjwt.ErrTokenAudienceInvalid) {
+		t.Errorf("expected validate options to apply, got %v", err)
+	}
+	if _, err := ReadCookie(r, []byte("another-secret-0123456789abcdef01"), CookieOptions{}); !errors.Is(err, sjwt.ErrTokenSignatureInvalid) {
+		t.Errorf("expected ErrTokenSignatureInvalid, got %v", err)
+	}
+	if _, err := ReadCookie(httptest.NewRequest(http.MethodGet, "/", nil), secretKey, CookieOptions{}); !errors.Is(err, ErrNoToken) {
+		t.Errorf("expected ErrNoToken, got %v", err)
+	}
+}
+
+func TestSetCookieOptions(t *testing.T) {
+	claims := sjwt.New()
+	opts := CookieOptions{Name: "session", Path: "/app", Domain: "example.com", SameSite: http.SameSiteStrictMode, Insecure: true}
+
+	w := httptest.NewRecorder()
+	if err := SetCookie(w, *claims, secretKey, opts); err != nil {
+		t.Fatalf("SetCookie returned error: %v", err)
+	}
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != "session" || cookie.Path != "/app" || cookie.Domain != "example.com" || cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie options were not applied, got: %+v", cookie)
+	}
+	if cookie.MaxAge != 0 || !cookie.HttpOnly {
+		t.Errorf("cookie without expires at should be a session cookie, got: %+v", cookie)
+	}
+
+	claims.SetExpiresIn(-time.Minute)
+	if err := SetCookie(httptest.NewRecorder(), *claims, secretKey, opts); !errors.Is(err, sjwt.ErrTokenHasExpired) {
+		t.Errorf("expected ErrTokenHasExpired, got %v", err)
+	}
+}
+
+func TestClearCookie(t *testing.T) {
+	w := httptest.NewRecorder()
+	ClearCookie(w, CookieOptions{Name: "session"})
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != "session" || cookie.Value != "" || cookie.MaxAge >= 0 || !cookie.Secure || !cookie.HttpOnly {
+		t.Errorf("cookie was not cleared, got: %+v", cookie)
+	}
+}
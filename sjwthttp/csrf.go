@@ -0,0 +1,78 @@
+package sjwthttp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/brianvoe/sjwt"
+)
+
+const (
+	// CSRFClaim holds the random CSRF secret embedded in the session token
+	CSRFClaim = "csrf"
+
+	// CSRFHeader is the request header carrying the CSRF token on unsafe methods
+	CSRFHeader = "X-CSRF-Token"
+
+	csrfSecretLength = 32
+)
+
+// ErrCSRFInvalid clarifies that the CSRF token is missing or does not match the session token
+var ErrCSRFInvalid = errors.New("csrf token invalid")
+
+// SetCSRF embeds a new random CSRF secret in the claims, setting a token id when missing,
+// and returns the CSRF token the client must echo in the X-CSRF-Token header.
+// Call it before generating the session token
+func SetCSRF(claims sjwt.Claims) (string, error) {
+	secret := make([]byte, csrfSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	claims.Set(CSRFClaim, base64.RawURLEncoding.EncodeToString(secret))
+	if !claims.Has(sjwt.TokenID) {
+		claims.SetTokenID()
+	}
+
+	return CSRFToken(claims)
+}
+
+// CSRFToken returns the CSRF token for the claims, the HMAC of the token id keyed with the CSRF secret
+func CSRFToken(claims sjwt.Claims) (string, error) {
+	secret, err := claims.GetStr(CSRFClaim)
+	if err != nil {
+		return "", err
+	}
+	tokenID, err := claims.GetTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(tokenID))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyCSRF checks the X-CSRF-Token header matches the claims on unsafe methods.
+// Safe methods such as GET are always allowed
+func VerifyCSRF(r *http.Request, claims sjwt.Claims) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+
+	expected, err := CSRFToken(claims)
+	if err != nil {
+		return ErrCSRFInvalid
+	}
+	actual := r.Header.Get(CSRFHeader)
+	if actual == "" || !hmac.Equal([]byte(actual), []byte(expected)) {
+		return ErrCSRFInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package sjwthttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/sjwt"
+)
+
+func TestCSRF(t *testing.T) {
+	claims := sjwt.New()
+	claims.SetExpiresIn(time.Hour)
+	csrfToken, err := SetCSRF(*claims)
+	if err != nil {
+		t.Fatalf("SetCSRF returned error: %v", err)
+	}
+	if !claims.Has(CSRFClaim) || !claims.Has(sjwt.TokenID) {
+		t.Fatal("SetCSRF should set the csrf secret and token id")
+	}
+
+	// Token is stable for the same claims and survives generate and parse
+	token, _ := claims.Generate(secretKey)
+	parsed, _ := sjwt.Parse(token)
+	if again, _ := CSRFToken(parsed); again != csrfToken {
+		t.Errorf("expected csrf token %s, got %s", csrfToken, again)
+	}
+
+	get := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := VerifyCSRF(get, parsed); err != nil {
+		t.Errorf("safe methods should not require csrf, got %v", err)
+	}
+
+	post := httptest.NewRequest(http.MethodPost, "/", nil)
+	if err := VerifyCSRF(post, parsed); !errors.Is(err, ErrCSRFInvalid) {
+		t.Errorf("expected ErrCSRFInvalid without header, got %v", err)
+	}
+	post.Header.Set(CSRFHeader, "forged")
+	if err := VerifyCSRF(post, parsed); !errors.Is(err, ErrCSRFInvalid) {
+		t.Errorf("expected ErrCSRFInvalid with wrong header, got %v", err)
+	}
+	post.Header.Set(CSRFHeader, csrfToken)
+	if err := VerifyCSRF(post, parsed); err != nil {
+		t.Errorf("expected matching header to pass, got %v", err)
+	}
+
+	// Tokens without a csrf secret cannot pass unsafe methods
+	if err := VerifyCSRF(post, *sjwt.New()); !errors.Is(err, ErrCSRFInvalid) {
+		t.Errorf("expected ErrCSRFInvalid without csrf claim, got %v", err)
+	}
+}
+
+func TestMiddlewareCSRF(t *testing.T) {
+	claims := sjwt.New()
+	claims.SetSubject("user:42")
+	claims.SetExpiresIn(time.Hour)
+	csrfToken, err := SetCSRF(*claims)
+	if err != nil {
+		t.Fatalf("SetCSRF returned error: %v", err)
+	}
+	token, _ := claims.Generate(secretKey)
+
+	handler := Middleware(Options{
+		Secret:    secretKey,
+		Extractor: CookieExtractor(DefaultCookieName),
+		CSRF:      true,
+	})(subjectHandler())
+
+	request := func(method string, csrf string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/", nil)
+		r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: token})
+		if csrf != "" {
+			r.Header.Set(CSRFHeader, csrf)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request(http.MethodGet, ""); w.Code != http.StatusOK {
+		t.Errorf("expected GET without csrf to pass, got %d", w.Code)
+	}
+	if w := request(http.MethodPost, ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected POST without csrf to be forbidden, got %d", w.Code)
+	}
+	if w := request(http.MethodDelete, "forged"); w.Code != http.StatusForbidden {
+		t.Errorf("expected DELETE with forged csrf to be forbidden, got %d", w.Code)
+	}
+	if w := request(http.MethodPost, csrfToken); w.Code != http.StatusOK {
+		t.Errorf("expected POST with csrf to pass, got %d", w.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package sjwthttp
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// TokenExtractor pulls the raw token out of a request.
+// It returns ErrNoToken when the request does not carry one
+type TokenExtractor interface {
+	ExtractToken(r *http.Request) (string, error)
+}
+
+// ExtractorFunc adapts a function to a TokenExtractor
+type ExtractorFunc func(r *http.Request) (string, error)
+
+// ExtractToken calls f(r)
+func (f ExtractorFunc) ExtractToken(r *http.Request) (string, error) { return f(r) }
+
+// BearerExtractor extracts the token from an `Authorization: Bearer` header
+func BearerExtractor() TokenExtractor { return authorizationExtractor(schemeBearer) }
+
+// DPoPExtractor extracts the token from an `Authorization: DPoP` header
+func DPoPExtractor() TokenExtractor { return authorizationExtractor(schemeDPoP) }
+
+// authorizationExtractor extracts the token from an Authorization header with the scheme
+func authorizationExtractor(authScheme string) TokenExtractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		values := r.Header.Values("Authorization")
+		if len(values) == 0 {
+			return "", ErrNoToken
+		}
+		if len(values) > 1 {
+			return "", ErrMalformedHeader
+		}
+
+		scheme, token, ok := strings.Cut(values[0], " ")
+		if !ok || !strings.EqualFold(scheme, authScheme) {
+			return "", ErrMalformedHeader
+		}
+		token = strings.TrimSpace(token)
+		if token == "" || strings.ContainsAny(token, " \t") {
+			return "", ErrMalformedHeader
+		}
+
+		return token, nil
+	})
+}
+
+// HeaderExtractor extracts the token from the whole value of a header, such as X-Auth-Token
+func HeaderExtractor(name string) TokenExtractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		token := strings.TrimSpace(r.Header.Get(name))
+		if token == "" {
+			return "", ErrNoToken
+		}
+
+		return token, nil
+	})
+}
+
+// CookieExtractor extracts the token from a cookie
+func CookieExtractor(name string) TokenExtractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return "", ErrNoToken
+		}
+
+		return cookie.Value, nil
+	})
+}
+
+// QueryExtractor extracts the token from a url query parameter, such as for websocket clients
+func QueryExtractor(param string) TokenExtractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		token := r.URL.Query().Get(param)
+		if token == "" {
+			return "", ErrNoToken
+		}
+
+		return token, nil
+	})
+}
+
+// FormExtractor extracts the token from an url encoded form body field, such as access_token
+func FormExtractor(field string) TokenExtractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		token := r.PostFormValue(field)
+		if token == "" {
+			return "", ErrNoToken
+		}
+
+		return token, nil
+	})
+}
+
+// ChainExtractor tries each extractor in order and returns the first token found.
+// Errors other than ErrNoToken, such as a malformed header, stop the chain
+func ChainExtractor(extractors ...TokenExtractor) TokenExtractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		for _, extractor := range extractors {
+			token, err := extractor.ExtractToken(r)
+			if errors.Is(err, ErrNoToken) {
+				continue
+			}
+
+			return token, err
+		}
+
+		return "", ErrNoToken
+	})
+}
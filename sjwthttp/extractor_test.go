@@ -0,0 +1,85 @@
+package sjwthttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtractors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/?access_token=query-token", strings.NewReader(url.Values{"access_token": {"form-token"}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Authorization", "Bearer header-token")
+	r.Header.Set("X-Auth-Token", "custom-token")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "cookie-token"})
+
+	tests := []struct {
+		name      string
+		extractor TokenExtractor
+		want      string
+	}{
+		{"bearer", BearerExtractor(), "header-token"},
+		{"header", HeaderExtractor("X-Auth-Token"), "custom-token"},
+		{"cookie", CookieExtractor("session"), "cookie-token"},
+		{"query", QueryExtractor("access_token"), "query-token"},
+		{"form", FormExtractor("access_token"), "form-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.extractor.ExtractToken(r)
+			if err != nil || token != tt.want {
+				t.Errorf("expected %s, got %s %v", tt.want, token, err)
+			}
+		})
+	}
+
+	empty := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, tt := range tests {
+		if _, err := tt.extractor.ExtractToken(empty); !errors.Is(err, ErrNoToken) {
+			t.Errorf("%s: expected ErrNoToken, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestChainExtractor(t *testing.T) {
+	chain := ChainExtractor(BearerExtractor(), CookieExtractor("session"), QueryExtractor("token"))
+
+	r := httptest.NewRequest(http.MethodGet, "/?token=query-token", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "cookie-token"})
+	if token, err := chain.ExtractToken(r); err != nil || token != "cookie-token" {
+		t.Errorf("expected cookie-token, got %s %v", token, err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/?token=query-token", nil)
+	if token, err := chain.ExtractToken(r); err != nil || token != "query-token" {
+		t.Errorf("expected query-token, got %s %v", token, err)
+	}
+
+	// Malformed credentials stop the chain
+	r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	if _, err := chain.ExtractToken(r); !errors.Is(err, ErrMalformedHeader) {
+		t.Errorf("expected ErrMalformedHeader, got %v", err)
+	}
+
+	if _, err := chain.ExtractToken(httptest.NewRequest(http.MethodGet, "/", nil)); !errors.Is(err, ErrNoToken) {
+		t.Errorf("expected ErrNoToken, got %v", err)
+	}
+}
+
+func TestMiddlewareExtractor(t *testing.T) {
+	handler := Middleware(Options{
+		Secret:    secretKey,
+		Extractor: ChainExtractor(BearerExtractor(), QueryExtractor("access_token")),
+	})(subjectHandler())
+
+	r := httptest.NewRequest(http.MethodGet, "/ws?access_token="+generate(t, nil), nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "user:42" {
+		t.Errorf("expected query token to authenticate, got %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,306 @@
+// Package sjwthttp provides net/http middleware for authenticating requests with sjwt tokens
+package sjwthttp
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/brianvoe/sjwt"
+)
+
+// RFC 6750 error codes
+const (
+	ErrorInvalidRequest    = "invalid_request"
+	ErrorInvalidToken      = "invalid_token"
+	ErrorInsufficientScope = "insufficient_scope"
+)
+
+// ErrorInvalidDPoPProof is the RFC 9449 error code for a missing or invalid DPoP proof
+const ErrorInvalidDPoPProof = "invalid_dpop_proof"
+
+const (
+	schemeBearer = "Bearer"
+	schemeDPoP   = "DPoP"
+)
+
+var (
+	// ErrNoToken clarifies that the request did not carry a token
+	ErrNoToken = errors.New("no token in request")
+
+	// ErrMalformedHeader clarifies that the Authorization header is not a valid bearer credential
+	ErrMalformedHeader = errors.New("malformed authorization header")
+
+	// ErrNoProof clarifies that a DPoP bound request did not carry exactly one DPoP proof header
+	ErrNoProof = errors.New("no dpop proof in request")
+
+	// ErrProofRequired clarifies that a DPoP bound token was presented where DPoP proofs are not checked
+	ErrProofRequired = errors.New("dpop bound token requires a dpop proof")
+
+	// ErrCertificateRequired clarifies that a certificate bound token was presented where client certificates are not checked
+	ErrCertificateRequired = errors.New("certificate bound token requires mutual tls")
+)
+
+// describedErrors are the error classes named in error_description,
+// anything else is described as sjwt.ErrTokenInvalid so no claim values or internal errors leak
+var describedErrors = []error{
+	ErrMalformedHeader,
+	ErrNoProof,
+	ErrProofRequired,
+	ErrCertificateRequired,
+	sjwt.ErrTokenHasExpired,
+	sjwt.ErrTokenNotYetValid,
+	sjwt.ErrTokenSignatureInvalid,
+	sjwt.ErrTokenAudienceInvalid,
+	sjwt.ErrTokenIssuerInvalid,
+	sjwt.ErrTokenRevoked,
+	sjwt.ErrTokenReplayed,
+}
+
+// Options configures Middleware
+type Options struct {
+	// Secret verifies the token signature
+	Secret []byte
+
+	// Validator validates the claims after the signature is verified,
+	// the expiration and not before checks of Claims.Validate run when nil
+	Validator *sjwt.Validator
+
+	// AccessToken validates RFC 9068 at+jwt access tokens instead of Secret and Validator when set
+	AccessToken *sjwt.AccessTokenValidator
+
+	// Realm is included in the WWW-Authenticate challenge when set
+	Realm string
+
+	// Extractor pulls the token out of the request, BearerExtractor when nil
+	// or DPoPExtractor when DPoP is set
+	Extractor TokenExtractor
+
+	// DPoP requires a RFC 9449 DPoP proof header bound to the access token when set.
+	// Without it DPoP bound tokens, those with a cnf jkt, are rejected
+	DPoP *sjwt.DPoPVerifier
+
+	// BaseURL is the scheme and host clients use, such as https://api.example.com
+	// behind a TLS terminating proxy. The DPoP proof url is BaseURL followed by r.URL.Path,
+	// or built from r.TLS and r.Host when empty
+	BaseURL string
+
+	// MTLS requires RFC 8705 tokens bound to the client certificate of the TLS connection,
+	// see Claims.SetCertificateThumbprint. Without it certificate bound tokens are rejected
+	MTLS bool
+
+	// CSRF requires a matching X-CSRF-Token header on unsafe methods, see SetCSRF.
+	// Enable it when the token is read from a cookie
+	CSRF bool
+
+	// OnError is called with the detailed error of every rejected request when set, such as to log it.
+	// Responses only carry a generic error_description
+	OnError func(r *http.Request, err error)
+}
+
+// Middleware authenticates requests with an `Authorization: Bearer` token, or the Extractor option.
+// Verified claims are stored in the request context, see FromContext.
+// Failures are answered with RFC 6750 challenges in the WWW-Authenticate header
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := opts.extractor().ExtractToken(r)
+			if err != nil {
+				opts.unauthorized(w, r, err)
+				return
+			}
+
+			claims, err := opts.authenticate(r, token)
+			if err != nil {
+				opts.unauthorized(w, r, err)
+				return
+			}
+
+			// A DPoP bound token is only usable with a proof of its key
+			if _, err := claims.GetJWKThumbprint(); opts.DPoP == nil && !errors.Is(err, sjwt.ErrNotFound) {
+				opts.unauthorized(w, r, ErrProofRequired)
+				return
+			}
+
+			// A certificate bound token is only usable on a connection with its certificate
+			if _, err := claims.GetCertificateThumbprint(); !opts.MTLS && !errors.Is(err, sjwt.ErrNotFound) {
+				opts.unauthorized(w, r, ErrCertificateRequired)
+				return
+			}
+
+			if opts.DPoP != nil {
+				if err := opts.verifyProof(r, token, claims); err != nil {
+					opts.onError(r, err)
+					description := sjwt.ErrTokenProofInvalid.Error()
+					if errors.Is(err, ErrNoProof) {
+						description = ErrNoProof.Error()
+					}
+					writeChallenge(w, http.StatusUnauthorized, schemeDPoP, opts.Realm, ErrorInvalidDPoPProof, description, "")
+					return
+				}
+			}
+
+			if opts.CSRF {
+				if err := VerifyCSRF(r, claims); err != nil {
+					opts.onError(r, err)
+					http.Error(w, ErrCSRFInvalid.Error(), http.StatusForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+		})
+	}
+}
+
+func (o Options) extractor() TokenExtractor {
+	if o.Extractor != nil {
+		return o.Extractor
+	}
+	if o.DPoP != nil {
+		return DPoPExtractor()
+	}
+
+	return BearerExtractor()
+}
+
+// authenticate verifies, parses and validates the token
+func (o Options) authenticate(r *http.Request, token string) (sjwt.Claims, error) {
+	var opts []sjwt.ValidateOption
+	if o.MTLS {
+		var cert *x509.Certificate
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			cert = r.TLS.PeerCertificates[0]
+		}
+		opts = append(opts, sjwt.WithCertificate(cert))
+	}
+
+	if o.AccessToken != nil {
+		return o.AccessToken.Validate(token, opts...)
+	}
+
+	return authenticate(token, o.Secret, o.Validator, opts...)
+}
+
+// authenticate verifies the signature, parses and validates the token with the validator
+// or with Claims.Validate when it is nil
+func authenticate(token string, secret []byte, validator *sjwt.Validator, opts ...sjwt.ValidateOption) (sjwt.Claims, error) {
+	if !sjwt.Verify(token, secret) {
+		return nil, sjwt.ErrTokenSignatureInvalid
+	}
+
+	claims, err := sjwt.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if validator != nil {
+		err = validator.Validate(claims, opts...)
+	} else {
+		err = claims.Validate(opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// verifyProof verifies the DPoP header proof against the request and the access token
+func (o Options) verifyProof(r *http.Request, token string, claims sjwt.Claims) error {
+	proofs := r.Header.Values(schemeDPoP)
+	if len(proofs) != 1 {
+		return ErrNoProof
+	}
+
+	baseURL := strings.TrimSuffix(o.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "http://" + r.Host
+		if r.TLS != nil {
+			baseURL = "https://" + r.Host
+		}
+	}
+	_, err := o.DPoP.Verify(proofs[0], sjwt.DPoPParams{
+		Method:            r.Method,
+		URL:               baseURL + r.URL.EscapedPath(),
+		AccessToken:       token,
+		AccessTokenClaims: claims,
+	})
+
+	return err
+}
+
+// onError passes the detailed error to OnError when set
+func (o Options) onError(r *http.Request, err error) {
+	if o.OnError != nil {
+		o.OnError(r, err)
+	}
+}
+
+// unauthorized writes the RFC 6750 response for err
+func (o Options) unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	o.onError(r, err)
+
+	scheme := schemeBearer
+	if o.DPoP != nil {
+		scheme = schemeDPoP
+	}
+
+	switch {
+	case errors.Is(err, ErrNoToken):
+		// No error code when the request had no authentication information
+		writeChallenge(w, http.StatusUnauthorized, scheme, o.Realm, "", "", "")
+	case errors.Is(err, ErrMalformedHeader):
+		writeChallenge(w, http.StatusBadRequest, scheme, o.Realm, ErrorInvalidRequest, describe(err), "")
+	default:
+		writeChallenge(w, http.StatusUnauthorized, scheme, o.Realm, ErrorInvalidToken, describe(err), "")
+	}
+}
+
+// describe returns a fixed error_description for the class of err
+func describe(err error) string {
+	for _, target := range describedErrors {
+		if errors.Is(err, target) {
+			return target.Error()
+		}
+	}
+
+	return sjwt.ErrTokenInvalid.Error()
+}
+
+// writeChallenge writes the status with a Bearer or DPoP WWW-Authenticate challenge
+func writeChallenge(w http.ResponseWriter, status int, scheme string, realm string, code string, description string, scope string) {
+	var params []string
+	if realm != "" {
+		params = append(params, fmt.Sprintf("realm=%q", quoteSafe(realm)))
+	}
+	if code != "" {
+		params = append(params, fmt.Sprintf("error=%q", code))
+	}
+	if description != "" {
+		params = append(params, fmt.Sprintf("error_description=%q", quoteSafe(description)))
+	}
+	if scope != "" {
+		params = append(params, fmt.Sprintf("scope=%q", quoteSafe(scope)))
+	}
+
+	challenge := scheme
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// quoteSafe drops characters RFC 6750 does not allow in quoted parameters
+func quoteSafe(s string) string {
+	s = strings.ReplaceAll(s, "\n", "; ")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,331 @@
+package sjwthttp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/sjwt"
+)
+
+var secretKey = []byte("0123456789abcdef0123456789abcdef")
+
+func generate(t *testing.T, build func(c *sjwt.Claims)) string {
+	t.Helper()
+
+	claims := sjwt.New()
+	claims.SetSubject("user:42")
+	claims.SetExpiresIn(time.Hour)
+	if build != nil {
+		build(claims)
+	}
+	token, err := claims.Generate(secretKey)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	return token
+}
+
+func subjectHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := FromContext(r.Context())
+		if !ok {
+			http.Error(w, "no claims", http.StatusInternalServerError)
+			return
+		}
+		subject, _ := claims.GetSubject()
+		w.Write([]byte(subject))
+	})
+}
+
+func serve(handler http.Handler, authorization ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, a := range authorization {
+		r.Header.Add("Authorization", a)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(Options{Secret: secretKey, Realm: "api"})(subjectHandler())
+
+	w := serve(handler, "Bearer "+generate(t, nil))
+	if w.Code != http.StatusOK || w.Body.String() != "user:42" {
+		t.Errorf("expected claims in context, got %d %s", w.Code, w.Body)
+	}
+
+	// Scheme is case insensitive
+	w = serve(handler, "bearer "+generate(t, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected lowercase scheme to be accepted, got %d", w.Code)
+	}
+}
+
+func TestMiddlewareChallenges(t *testing.T) {
+	handler := Middleware(Options{Secret: secretKey, Realm: "api"})(subjectHandler())
+	expired := generate(t, func(c *sjwt.Claims) { c.SetExpiresIn(-time.Minute) })
+	otherSecret, _ := sjwt.New().Generate([]byte("another-secret-0123456789abcdef01"))
+
+	tests := []struct {
+		name          string
+		authorization []string
+		status        int
+		challenge     string
+	}{
+		{"missing", nil, http.StatusUnauthorized, `Bearer realm="api"`},
+		{"basic scheme", []string{"Basic dXNlcjpwYXNz"}, http.StatusBadRequest, `error="invalid_request"`},
+		{"empty token", []string{"Bearer "}, http.StatusBadRequest, `error="invalid_request"`},
+		{"multiple headers", []string{"Bearer a", "Bearer b"}, http.StatusBadRequest, `error="invalid_request"`},
+		{"bad signature", []string{"Bearer " + otherSecret}, http.StatusUnauthorized, `error="invalid_token", error_description="token signature invalid"`},
+		{"expired", []string{"Bearer " + expired}, http.StatusUnauthorized, `error="invalid_token", error_description="token has expired"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(handler, tt.authorization...)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			challenge := w.Header().Get("WWW-Authenticate")
+			if !strings.HasPrefix(challenge, `Bearer realm="api"`) || !strings.Contains(challenge, tt.challenge) {
+				t.Errorf("expected challenge containing %s, got %s", tt.challenge, challenge)
+			}
+		})
+	}
+}
+
+func TestMiddlewareValidator(t *testing.T) {
+	validator := sjwt.NewValidator(sjwt.WithAudience("api"))
+	handler := Middleware(Options{Secret: secretKey, Validator: validator})(subjectHandler())
+
+	w := serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetAudience([]string{"web"}) }))
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Header().Get("WWW-Authenticate"), "token audience invalid") {
+		t.Errorf("expected audience failure, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+
+	w = serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetAudience([]string{"api"}) }))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected success, got %d", w.Code)
+	}
+}
+
+func TestMiddlewareErrorDescriptions(t *testing.T) {
+	var detailed error
+	handler := Middleware(Options{
+		Secret:    secretKey,
+		Validator: sjwt.NewValidator(sjwt.WithAudience("api"), sjwt.WithRequired("tenant")),
+		OnError:   func(_ *http.Request, err error) { detailed = err },
+	})(subjectHandler())
+
+	// Expected values and claim names only reach OnError
+	w := serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetAudience([]string{"web"}) }))
+	challenge := w.Header().Get("WWW-Authenticate")
+	if challenge != `Bearer error="invalid_token", error_description="token audience invalid"` {
+		t.Errorf("expected a generic description, got %s", challenge)
+	}
+	var validationErr *sjwt.ValidationError
+	if !errors.As(detailed, &validationErr) || !strings.Contains(detailed.Error(), "tenant") {
+		t.Errorf("expected the detailed error in OnError, got %v", detailed)
+	}
+
+	// Other failures are described as an invalid token
+	w = serve(handler, "Bearer "+generate(t, func(c *sjwt.Claims) { c.SetAudience([]string{"api"}) }))
+	if challenge := w.Header().Get("WWW-Authenticate"); challenge != `Bearer error="invalid_token", error_description="token is invalid"` {
+		t.Errorf("expected a generic description, got %s", challenge)
+	}
+}
+
+func TestFromContextEmpty(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := FromContext(r.Context()); ok {
+		t.Error("expected no claims in an empty context")
+	}
+}
+
+func TestMiddlewareAccessToken(t *testing.T) {
+	issuer := sjwt.NewAccessTokenIssuer("https://as.example.com", secretKey)
+	validator := sjwt.NewAccessTokenValidator("https://as.example.com", "https://api.example.com", secretKey)
+	handler := Middleware(Options{AccessToken: validator})(subjectHandler())
+
+	token, err := issuer.Issue(sjwt.AccessTokenParams{Subject: "user:42", ClientID: "client", Audience: []string{"https://api.example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := serve(handler, "Bearer "+token)
+	if w.Code != http.StatusOK || w.Body.String() != "user:42" {
+		t.Errorf("expected success, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Plain JWTs are rejected
+	w = serve(handler, "Bearer "+generate(t, nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a plain JWT, got %d", w.Code)
+	}
+}
+
+func TestMiddlewareDPoP(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, _ := sjwt.NewJWK(&key.PublicKey)
+	handler := Middleware(Options{Secret: secretKey, DPoP: sjwt.NewDPoPVerifier(sjwt.NewReplayCache())})(subjectHandler())
+
+	token := generate(t, func(c *sjwt.Claims) { c.SetJWKThumbprint(jwk.Thumbprint()) })
+	request := func(proof string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://api.example.com/orders?page=2", nil)
+		req.Header.Set("Authorization", "DPoP "+token)
+		if proof != "" {
+			req.Header.Set("DPoP", proof)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	proof, _ := sjwt.NewDPoPProof(key, http.MethodGet, "http://api.example.com/orders", token)
+	w := request(proof)
+	if w.Code != http.StatusOK || w.Body.String() != "user:42" {
+		t.Errorf("expected success, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Replayed and missing proofs
+	for _, proof := range []string{proof, ""} {
+		w = request(proof)
+		challenge := w.Header().Get("WWW-Authenticate")
+		if w.Code != http.StatusUnauthorized || !strings.HasPrefix(challenge, `DPoP error="invalid_dpop_proof"`) {
+			t.Errorf("expected invalid_dpop_proof, got %d %s", w.Code, challenge)
+		}
+	}
+
+	// Proofs from another key
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	proof, _ = sjwt.NewDPoPProof(otherKey, http.MethodGet, "http://api.example.com/orders", token)
+	if w = request(proof); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for another key, got %d", w.Code)
+	}
+
+	// Bearer tokens are not accepted
+	w = serve(handler, "Bearer "+token)
+	if w.Code != http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "DPoP ") {
+		t.Errorf("expected DPoP challenge, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+
+	// DPoP bound tokens are not accepted as plain bearer tokens
+	w = serve(Middleware(Options{Secret: secretKey})(subjectHandler()), "Bearer "+token)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Header().Get("WWW-Authenticate"), `error_description="dpop bound token requires a dpop proof"`) {
+		t.Errorf("expected DPoP bound token to be rejected, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func TestMiddlewareDPoPBaseURL(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, _ := sjwt.NewJWK(&key.PublicKey)
+	handler := Middleware(Options{
+		Secret:  secretKey,
+		DPoP:    sjwt.NewDPoPVerifier(sjwt.NewReplayCache()),
+		BaseURL: "https://api.example.com/",
+	})(subjectHandler())
+
+	// The proxy terminated TLS and forwarded the request to an internal host
+	token := generate(t, func(c *sjwt.Claims) { c.SetJWKThumbprint(jwk.Thumbprint()) })
+	proof, _ := sjwt.NewDPoPProof(key, http.MethodGet, "https://api.example.com/orders", token)
+	req := httptest.NewRequest(http.MethodGet, "http://10.0.0.7:8080/orders", nil)
+	req.Header.Set("Authorization", "DPoP "+token)
+	req.Header.Set("DPoP", proof)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected success behind a proxy, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func newClientCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestMiddlewareMTLS(t *testing.T) {
+	clientCert := newClientCertificate(t)
+	token := generate(t, func(c *sjwt.Claims) { c.SetCertificateThumbprint(clientCert.Leaf) })
+	otherToken := generate(t, func(c *sjwt.Claims) { c.SetCertificateThumbprint(newClientCertificate(t).Leaf) })
+
+	// Ask clients for a certificate, the chain is checked by the mesh in production
+	server := httptest.NewUnstartedServer(Middleware(Options{Secret: secretKey, MTLS: true})(subjectHandler()))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(client *http.Client, token string) int {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	client := server.Client()
+	client.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{clientCert}
+	if status := get(client, token); status != http.StatusOK {
+		t.Errorf("expected success, got %d", status)
+	}
+	if status := get(client, otherToken); status != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a token bound to another certificate, got %d", status)
+	}
+	if status := get(client, generate(t, nil)); status != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an unbound token, got %d", status)
+	}
+
+	// Without TLS there is no client certificate
+	w := serve(Middleware(Options{Secret: secretKey, MTLS: true})(subjectHandler()), "Bearer "+token)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without TLS, got %d", w.Code)
+	}
+
+	// Certificate bound tokens are not accepted as plain bearer tokens
+	w = serve(Middleware(Options{Secret: secretKey})(subjectHandler()), "Bearer "+token)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Header().Get("WWW-Authenticate"), `error_description="certificate bound token requires mutual tls"`) {
+		t.Errorf("expected certificate bound token to be rejected, got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
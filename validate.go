@@ -0,0 +1,125 @@
+package sjwt
+
+import (
+	"crypto/x509"
+	"slices"
+	"time"
+)
+
+// ValidateOption configures additional checks run by Validate
+type ValidateOption func(*validateOptions)
+
+// Validator validates claims with a shared set of options,
+// such as the claims every token from your issuer must have
+type Validator struct {
+	opts []ValidateOption
+}
+
+// NewValidator will initiate a new validator with the options
+func NewValidator(opts ...ValidateOption) *Validator {
+	return &Validator{opts: slices.Clone(opts)}
+}
+
+// Validate checks the claims with the validator options followed by the per call options
+func (v *Validator) Validate(c Claims, opts ...ValidateOption) error {
+	return c.Validate(append(slices.Clone(v.opts), opts...)...)
+}
+
+type validateOptions struct {
+	clock            func() time.Time
+	leeway           time.Duration
+	maxLifetime      time.Duration
+	maxAge           time.Duration
+	noFutureIssuedAt bool
+	required         []string
+	audiences        []string
+	issuers          []string
+	schemas          []Schema
+	revocations      []RevocationStore
+	replay           *ReplayCache
+	certificateBound bool
+	certificate      *x509.Certificate
+}
+
+func newValidateOptions(opts []ValidateOption) *validateOptions {
+	o := &validateOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func (o *validateOptions) now() time.Time {
+	if o.clock != nil {
+		return o.clock()
+	}
+
+	return time.Now()
+}
+
+// WithClock sets the function used to get the current time, useful for testing
+func WithClock(clock func() time.Time) ValidateOption {
+	return func(o *validateOptions) { o.clock = clock }
+}
+
+// WithLeeway allows for clock skew between servers when checking time based claims
+func WithLeeway(leeway time.Duration) ValidateOption {
+	return func(o *validateOptions) { o.leeway = leeway }
+}
+
+// WithMaxLifetime rejects tokens whose expires at is more than lifetime after issued at,
+// or after now when issued at is not set. Tokens without expires at are rejected as well
+func WithMaxLifetime(lifetime time.Duration) ValidateOption {
+	return func(o *validateOptions) { o.maxLifetime = lifetime }
+}
+
+// WithMaxAge rejects tokens that were issued more than age ago, issued at is required
+func WithMaxAge(age time.Duration) ValidateOption {
+	return func(o *validateOptions) { o.maxAge = age }
+}
+
+// WithNoFutureIssuedAt rejects tokens whose issued at is in the future beyond the leeway
+func WithNoFutureIssuedAt() ValidateOption {
+	return func(o *validateOptions) { o.noFutureIssuedAt = true }
+}
+
+// WithRequired requires the claims to be present, such as ExpiresAt so tokens cannot be valid forever
+func WithRequired(names ...string) ValidateOption {
+	return func(o *validateOptions) { o.required = append(o.required, names...) }
+}
+
+// WithAudience requires the audience claim to contain at least one of the expected audiences
+func WithAudience(audiences ...string) ValidateOption {
+	return func(o *validateOptions) { o.audiences = append(o.audiences, audiences...) }
+}
+
+// WithIssuer requires the issuer claim to equal one of the expected issuers
+func WithIssuer(issuers ...string) ValidateOption {
+	return func(o *validateOptions) { o.issuers = append(o.issuers, issuers...) }
+}
+
+// WithSchema requires the claims to satisfy the schema, each violation is reported as a *ValidationError
+func WithSchema(schema Schema) ValidateOption {
+	return func(o *validateOptions) { o.schemas = append(o.schemas, schema) }
+}
+
+// WithRevocationStore rejects tokens whose token id has been revoked in the store, token id is required
+func WithRevocationStore(store RevocationStore) ValidateOption {
+	return func(o *validateOptions) { o.revocations = append(o.revocations, store) }
+}
+
+// WithCertificate requires the token to be bound to the mutual TLS client certificate
+// with the cnf x5t#S256 claim, such as r.TLS.PeerCertificates[0]. A nil certificate always fails
+func WithCertificate(cert *x509.Certificate) ValidateOption {
+	return func(o *validateOptions) {
+		o.certificateBound = true
+		o.certificate = cert
+	}
+}
+
+// WithReplayCache accepts each token id only once, token id and expires at are required.
+// The token id is only recorded when every other check passes, so verify the signature first
+func WithReplayCache(cache *ReplayCache) ValidateOption {
+	return func(o *validateOptions) { o.replay = cache }
+}
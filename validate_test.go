@@ -0,0 +1,173 @@
+package sjwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateRequired(t *testing.T) {
+	claims := New()
+	claims.SetIssuer("issuer.example")
+
+	err := claims.Validate(WithRequired(ExpiresAt, Issuer, TokenID, ExpiresAt))
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("expected 2 missing claims, got %v", err)
+	}
+	for i, want := range []string{ExpiresAt, TokenID} {
+		var validationErr *ValidationError
+		if !errors.As(joined.Unwrap()[i], &validationErr) || validationErr.Claim != want {
+			t.Errorf("expected missing claim %s, got %v", want, joined.Unwrap()[i])
+		}
+	}
+
+	claims.SetExpiresIn(time.Hour)
+	claims.SetTokenID()
+	if err := claims.Validate(WithRequired(ExpiresAt, Issuer, TokenID)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	validator := NewValidator(WithRequired(ExpiresAt, IssuedAt))
+
+	claims := New()
+	claims.SetExpiresIn(time.Hour)
+	err := validator.Validate(*claims)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Claim != IssuedAt || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing iat, got %v", err)
+	}
+
+	claims.SetIssuedAt(time.Now())
+	if err := validator.Validate(*claims); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	// Per call options add to the validator options
+	if err := validator.Validate(*claims, WithRequired(TokenID)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing jti, got %v", err)
+	}
+	if err := validator.Validate(*claims); err != nil {
+		t.Errorf("per call options should not leak into the validator: %v", err)
+	}
+}
+
+func TestValidateLeeway(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := WithClock(func() time.Time { return now })
+
+	claims := New()
+	claims.SetExpiresAt(now.Add(-10 * time.Second))
+	claims.SetNotBeforeAt(now.Add(10 * time.Second))
+	if err := claims.Validate(clock); !errors.Is(err, ErrTokenHasExpired) || !errors.Is(err, ErrTokenNotYetValid) {
+		t.Errorf("expected expired and not yet valid, got %v", err)
+	}
+	if err := claims.Validate(clock, WithLeeway(30*time.Second)); err != nil {
+		t.Errorf("leeway should allow clock skew, got %v", err)
+	}
+}
+
+func TestValidateIssuedAt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := WithClock(func() time.Time { return now })
+
+	// Issued in the future
+	claims := New()
+	claims.SetIssuedAt(now.Add(time.Minute))
+	if err := claims.Validate(clock); err != nil {
+		t.Errorf("future issued at is only checked when enabled, got %v", err)
+	}
+	if err := claims.Validate(clock, WithNoFutureIssuedAt()); !errors.Is(err, ErrTokenIssuedInFuture) {
+		t.Errorf("expected ErrTokenIssuedInFuture, got %v", err)
+	}
+	if err := claims.Validate(clock, WithNoFutureIssuedAt(), WithLeeway(2*time.Minute)); err != nil {
+		t.Errorf("leeway should allow future issued at, got %v", err)
+	}
+
+	// Max age
+	claims.SetIssuedAt(now.Add(-2 * time.Hour))
+	err := claims.Validate(clock, WithMaxAge(time.Hour))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrTokenTooOld) || validationErr.Actual != 2*time.Hour {
+		t.Errorf("expected ErrTokenTooOld, got %v", err)
+	}
+	if err := claims.Validate(clock, WithMaxAge(3*time.Hour)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+	claims.DeleteIssuedAt()
+	if err := claims.Validate(clock, WithMaxAge(time.Hour)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound without issued at, got %v", err)
+	}
+}
+
+func TestValidateMaxLifetime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := WithClock(func() time.Time { return now })
+
+	claims := New()
+	claims.SetIssuedAt(now.Add(-time.Minute))
+	claims.SetExpiresAt(now.Add(365 * 24 * time.Hour))
+	if err := claims.Validate(clock, WithMaxLifetime(24*time.Hour)); !errors.Is(err, ErrTokenLifetimeTooLong) {
+		t.Errorf("expected ErrTokenLifetimeTooLong, got %v", err)
+	}
+
+	claims.SetExpiresAt(now.Add(time.Hour))
+	if err := claims.Validate(clock, WithMaxLifetime(24*time.Hour)); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	// Without issued at the lifetime is measured from now
+	claims.DeleteIssuedAt()
+	if err := claims.Validate(clock, WithMaxLifetime(30*time.Minute)); !errors.Is(err, ErrTokenLifetimeTooLong) {
+		t.Errorf("expected ErrTokenLifetimeTooLong, got %v", err)
+	}
+
+	// Without expires at the lifetime is unbounded
+	claims.DeleteExpiresAt()
+	if err := claims.Validate(clock, WithMaxLifetime(24*time.Hour)); !errors.Is(err, ErrTokenLifetimeTooLong) {
+		t.Errorf("expected ErrTokenLifetimeTooLong, got %v", err)
+	}
+}
+
+func TestValidateIssuer(t *testing.T) {
+	claims := New()
+	claims.SetIssuer("https://issuer.example")
+
+	if err := claims.Validate(WithIssuer("https://other.example", "https://issuer.example")); err != nil {
+		t.Errorf("Validate was not successful when it should be: %v", err)
+	}
+
+	err := claims.Validate(WithIssuer("https://other.example"))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Claim != Issuer || !errors.Is(err, ErrTokenIssuerInvalid) {
+		t.Errorf("expected ErrTokenIssuerInvalid, got %v", err)
+	}
+
+	if err := New().Validate(WithIssuer("https://issuer.example")); !errors.Is(err, ErrTokenIssuerInvalid) {
+		t.Errorf("expected missing issuer to be invalid, got %v", err)
+	}
+}
+
+func TestValidateMalformedIssuedAt(t *testing.T) {
+	claims := Claims{IssuedAt: "yesterday"}
+
+	err := claims.Validate(WithMaxAge(time.Hour))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Claim != IssuedAt || !errors.Is(err, ErrClaimValueInvalid) || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrClaimValueInvalid for malformed iat, got %v", err)
+	}
+
+	if err := claims.Validate(WithNoFutureIssuedAt()); !errors.Is(err, ErrClaimValueInvalid) {
+		t.Errorf("expected ErrClaimValueInvalid for malformed iat, got %v", err)
+	}
+
+	if err := New().Validate(WithMaxAge(time.Hour)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing iat, got %v", err)
+	}
+}